@@ -1,11 +1,16 @@
 package config
 
 import (
+	"autonity-oracle/types"
+	"crypto/ed25519"
+	"encoding/hex"
 	"fmt"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/hashicorp/go-hclog"
 	"gopkg.in/yaml.v2"
 	"log"
+	"net/url"
 	"os"
 	"strings"
 	"time"
@@ -32,6 +37,8 @@ const Version uint8 = 24
 
 const PreSamplingRange = 6 // pre-sampling starts in 6s in advance
 
+const SampleTTL = 30 // the TTL of the plugins' samples in seconds, the older samples are dropped.
+
 // MetricsNameSpace is the name space of oracle-server's metrics in influxDB.
 const MetricsNameSpace = "autoracle."
 const MetricsInterval = time.Second * 10
@@ -49,6 +56,10 @@ var DefaultConfig = ServerConfig{
 	ConfidenceStrategy: defaultConfidenceStrategy,
 	PluginConfigs:      nil,
 	MetricConfigs:      DefaultMetricConfig,
+	LogConfigs:         DefaultLogConfig,
+	TraceConfigs:       DefaultTraceConfig,
+	IntegrityConfigs:   DefaultIntegrityConfig,
+	SnapshotConfigs:    DefaultSnapshotConfig,
 }
 
 // DefaultMetricConfig is the default config for metrics used in oracle-server.
@@ -70,6 +81,38 @@ var DefaultMetricConfig = MetricConfig{
 	InfluxDBOrganization: "autonity",
 }
 
+// DefaultLogConfig is the default config for logging used in oracle-server.
+var DefaultLogConfig = LogConfig{
+	Format:     LogFormatText,
+	Output:     LogOutputStdout,
+	File:       "./autoracle.log",
+	MaxSize:    100, // 100MB
+	MaxAge:     7,   // 7 days
+	MaxBackups: 10,
+}
+
+// DefaultTraceConfig is the default config for tracing used in oracle-server, all the exporters are disabled.
+var DefaultTraceConfig = TraceConfig{
+	EnableOTLP:   false,
+	OTLPEndpoint: "http://localhost:4318/v1/traces",
+	Exporter:     TraceExporterNone,
+	File:         "./traces.json",
+}
+
+// DefaultIntegrityConfig is the default config for the plugin integrity verification, the plugins with a pinned checksum
+// are verified, while the unknown plugins are still launched.
+var DefaultIntegrityConfig = IntegrityConfig{
+	Enforce:   false,
+	PublicKey: "",
+}
+
+// DefaultSnapshotConfig is the default config for the snapshots of the plugins' samples, it is disabled by default.
+var DefaultSnapshotConfig = SnapshotConfig{
+	Enable:   false,
+	Dir:      "./snapshots",
+	Interval: 10, // 10s, it is shorter than the SampleTTL, otherwise the reloaded snapshot is mostly expired.
+}
+
 // MetricConfig contains the configuration for the metric collection of oracle-server.
 type MetricConfig struct {
 	// Common configs for influxDB V1 and V2.
@@ -89,34 +132,169 @@ type MetricConfig struct {
 	InfluxDBOrganization string `json:"influxDBOrganization" yaml:"influxDBOrganization"`
 }
 
+// LogConfig contains the configuration for the logging of oracle-server, the levels of the components are taking the
+// value of the logLevel if they are not set.
+type LogConfig struct {
+	Format         string         `json:"format" yaml:"format"`                 // The log format, text or json.
+	Output         string         `json:"output" yaml:"output"`                 // The log output, stdout or file.
+	File           string         `json:"file" yaml:"file"`                     // The log file path if the output is file.
+	MaxSize        int            `json:"maxSize" yaml:"maxSize"`               // The maximum size in megabytes of the log file before it gets rotated.
+	MaxAge         int            `json:"maxAge" yaml:"maxAge"`                 // The maximum number of days to retain the rotated log files.
+	MaxBackups     int            `json:"maxBackups" yaml:"maxBackups"`         // The maximum number of the rotated log files to retain.
+	RotateInterval int            `json:"rotateInterval" yaml:"rotateInterval"` // The interval in hours to rotate the log file regardless of its size, 0 to disable it.
+	ServerLevel    int            `json:"serverLevel" yaml:"serverLevel"`       // The logging verbosity of the oracle server.
+	PluginLevel    int            `json:"pluginLevel" yaml:"pluginLevel"`       // The logging verbosity of the plugin wrappers.
+	PluginLevels   map[string]int `json:"pluginLevels" yaml:"pluginLevels"`     // The logging verbosity of individual plugins by the plugin name.
+}
+
+// TraceConfig contains the configuration for the round-scoped tracing of oracle-server, the spans can be exported to an
+// OTLP/HTTP collector, and to the stdout or a file for local debugging.
+type TraceConfig struct {
+	EnableOTLP   bool   `json:"enableOTLP" yaml:"enableOTLP"`     // The flag to export the spans to an OTLP/HTTP collector.
+	OTLPEndpoint string `json:"otlpEndpoint" yaml:"otlpEndpoint"` // The OTLP/HTTP traces endpoint of the collector.
+	Exporter     string `json:"exporter" yaml:"exporter"`         // The local exporter for debugging, none, stdout or file.
+	File         string `json:"file" yaml:"file"`                 // The file to export the spans into if the local exporter is file.
+}
+
 // ServerConfig is the schema of oracle-server's config.
 type ServerConfig struct {
-	LoggingLevel       int            `json:"logLevel" yaml:"logLevel"`
-	GasTipCap          uint64         `json:"gasTipCap" yaml:"gasTipCap"`
-	VoteBuffer         uint64         `json:"voteBuffer" yaml:"voteBuffer"`
-	KeyFile            string         `json:"keyFile" yaml:"keyFile"`
-	KeyPassword        string         `json:"keyPassword" yaml:"keyPassword"`
-	AutonityWSUrl      string         `json:"autonityWSUrl" yaml:"autonityWSUrl"`
-	PluginDIR          string         `json:"pluginDir" yaml:"pluginDir"`
-	ProfileDir         string         `json:"profileDir" yaml:"profileDir"`
-	ConfidenceStrategy int            `json:"confidenceStrategy" yaml:"confidenceStrategy"`
-	PluginConfigs      []PluginConfig `json:"pluginConfigs" yaml:"pluginConfigs"`
-	MetricConfigs      MetricConfig   `json:"metricConfigs" yaml:"metricConfigs"`
+	LoggingLevel       int             `json:"logLevel" yaml:"logLevel"`
+	GasTipCap          uint64          `json:"gasTipCap" yaml:"gasTipCap"`
+	VoteBuffer         uint64          `json:"voteBuffer" yaml:"voteBuffer"`
+	KeyFile            string          `json:"keyFile" yaml:"keyFile"`
+	KeyPassword        string          `json:"keyPassword" yaml:"keyPassword"`
+	AutonityWSUrl      string          `json:"autonityWSUrl" yaml:"autonityWSUrl"`
+	OracleContract     string          `json:"oracleContractAddress" yaml:"oracleContractAddress"`
+	PluginDIR          string          `json:"pluginDir" yaml:"pluginDir"`
+	ProfileDir         string          `json:"profileDir" yaml:"profileDir"`
+	ConfidenceStrategy int             `json:"confidenceStrategy" yaml:"confidenceStrategy"`
+	PluginConfigs      []PluginConfig  `json:"pluginConfigs" yaml:"pluginConfigs"`
+	MetricConfigs      MetricConfig    `json:"metricConfigs" yaml:"metricConfigs"`
+	LogConfigs         LogConfig       `json:"logConfigs" yaml:"logConfigs"`
+	TraceConfigs       TraceConfig     `json:"traceConfigs" yaml:"traceConfigs"`
+	IntegrityConfigs   IntegrityConfig `json:"integrityConfigs" yaml:"integrityConfigs"`
+	SnapshotConfigs    SnapshotConfig  `json:"snapshotConfigs" yaml:"snapshotConfigs"`
+}
+
+// SnapshotConfig contains the configuration of the snapshots of the plugins' recent samples, they are written
+// periodically and on the shutdown, and they are reloaded on the startup, thus the first round after a restart is not
+// reported from a few seconds of samples only.
+type SnapshotConfig struct {
+	Enable   bool   `json:"enable" yaml:"enable"`     // The flag to write and reload the snapshots of the samples.
+	Dir      string `json:"dir" yaml:"dir"`           // The directory to write the snapshots into, one file per plugin.
+	Interval int    `json:"interval" yaml:"interval"` // The interval in seconds to write the snapshots.
+}
+
+// IntegrityConfig contains the configuration of the plugin binaries' integrity verification.
+type IntegrityConfig struct {
+	Enforce   bool   `json:"enforce" yaml:"enforce"`     // The flag to refuse the plugins that are neither pinned by a checksum nor signed.
+	PublicKey string `json:"publicKey" yaml:"publicKey"` // The hex encoded ed25519 public key of the operator to verify the detached signatures of the plugins.
 }
 
 // PluginConfig is the schema of plugins' config.
 type PluginConfig struct {
-	Name               string `json:"name" yaml:"name"`                         // The name of the plugin binary.
-	Key                string `json:"key" yaml:"key"`                           // The API key granted by your data provider to access their data API.
-	Scheme             string `json:"scheme" yaml:"scheme"`                     // The data service scheme, http or https.
-	Endpoint           string `json:"endpoint" yaml:"endpoint"`                 // The data service endpoint url of the data provider.
-	Timeout            int    `json:"timeout" yaml:"timeout"`                   // The timeout period in seconds that an API request is lasting for.
-	DataUpdateInterval int    `json:"refresh" yaml:"refresh"`                   // The interval in seconds to fetch data from data provider due to rate limit.
-	NTNTokenAddress    string `json:"ntnTokenAddress" yaml:"ntnTokenAddress"`   // The NTN erc20 token address on the target blockchain.
-	ATNTokenAddress    string `json:"atnTokenAddress" yaml:"atnTokenAddress"`   // The Wrapped ATN erc20 token address on the target blockchain.
-	USDCTokenAddress   string `json:"usdcTokenAddress" yaml:"usdcTokenAddress"` // The USDC erc20 token address on the target blockchain.
-	SwapAddress        string `json:"swapAddress" yaml:"swapAddress"`           // The UniSwap factory contract address or AirSwap SwapERC20 contract address on the target blockchain.
-	Disabled           bool   `json:"disabled" yaml:"disabled"`                 // The flag to disable a plugin.
+	Name               string         `json:"name" yaml:"name"`                         // The name of the plugin binary.
+	Key                string         `json:"key" yaml:"key"`                           // The API key granted by your data provider to access their data API.
+	Scheme             string         `json:"scheme" yaml:"scheme"`                     // The data service scheme, http or https.
+	Endpoint           string         `json:"endpoint" yaml:"endpoint"`                 // The data service endpoint url of the data provider.
+	Timeout            int            `json:"timeout" yaml:"timeout"`                   // The timeout period in seconds that an API request is lasting for.
+	DataUpdateInterval int            `json:"refresh" yaml:"refresh"`                   // The interval in seconds to fetch data from data provider due to rate limit.
+	NTNTokenAddress    string         `json:"ntnTokenAddress" yaml:"ntnTokenAddress"`   // The NTN erc20 token address on the target blockchain.
+	ATNTokenAddress    string         `json:"atnTokenAddress" yaml:"atnTokenAddress"`   // The Wrapped ATN erc20 token address on the target blockchain.
+	USDCTokenAddress   string         `json:"usdcTokenAddress" yaml:"usdcTokenAddress"` // The USDC erc20 token address on the target blockchain.
+	SwapAddress        string         `json:"swapAddress" yaml:"swapAddress"`           // The UniSwap factory contract address or AirSwap SwapERC20 contract address on the target blockchain.
+	Disabled           bool           `json:"disabled" yaml:"disabled"`                 // The flag to disable a plugin.
+	Checksum           string         `json:"checksum" yaml:"checksum"`                 // The hex encoded SHA-256 checksum pinned for the plugin binary.
+	Limits             ResourceLimits `json:"limits" yaml:"limits"`                     // The resource limits of the plugin process.
+	InProcess          bool           `json:"inProcess" yaml:"inProcess"`               // The flag to run a plugin linked into the oracle server in process rather than its binary.
+	SymbolMap          SymbolMap      `json:"symbolMap" yaml:"symbolMap"`               // The explicit mapping of the protocol symbols to the tickers of the data provider.
+	Budget             RequestBudget  `json:"budget" yaml:"budget"`                     // The request budget of the data provider.
+	HTTP               HTTPConfig     `json:"http" yaml:"http"`                         // The HTTP client settings of the plugin.
+	LegacyConfEnv      bool           `json:"legacyConfEnv" yaml:"legacyConfEnv"`       // The flag to pass the config in the env of the plugin process as well, for the plugins built before the config file.
+}
+
+// ResourceLimits is the schema of the resource limits of a plugin process, they are applied on Linux only, and the
+// zero values stand for no limit.
+type ResourceLimits struct {
+	MaxRSS       int     `json:"maxRSS" yaml:"maxRSS"`             // The maximum resident memory in megabytes, the plugin is restarted once it is exceeded.
+	MaxCPUTime   int     `json:"maxCPUTime" yaml:"maxCPUTime"`     // The maximum CPU time in seconds of the plugin process.
+	MaxOpenFiles int     `json:"maxOpenFiles" yaml:"maxOpenFiles"` // The maximum number of the open files of the plugin process.
+	Cgroup       string  `json:"cgroup" yaml:"cgroup"`             // The cgroup v2 directory to place the plugin process in, it is created if it does not exist.
+	CPUQuota     float64 `json:"cpuQuota" yaml:"cpuQuota"`         // The CPU quota in cores of the plugin's cgroup, for example 0.5.
+	UID          int     `json:"uid" yaml:"uid"`                   // The user ID to run the plugin process, it requires the oracle server to run as root.
+	GID          int     `json:"gid" yaml:"gid"`                   // The group ID to run the plugin process, it requires the oracle server to run as root.
+}
+
+// Validate checks the resource limits.
+func (rl *ResourceLimits) Validate() error {
+	if rl.MaxRSS < 0 || rl.MaxCPUTime < 0 || rl.MaxOpenFiles < 0 || rl.CPUQuota < 0 || rl.UID < 0 || rl.GID < 0 {
+		return fmt.Errorf("the resource limits cannot be negative")
+	}
+	if (rl.UID == 0) != (rl.GID == 0) {
+		return fmt.Errorf("the uid and the gid are required to be set together")
+	}
+	if rl.CPUQuota > 0 && rl.Cgroup == "" {
+		return fmt.Errorf("the cgroup is missing for the cpu quota")
+	}
+	return nil
+}
+
+// RequestBudget is the schema of the request budget of a data provider, it is applied by the plugins built on the
+// common client, and the zero values stand for no limit.
+type RequestBudget struct {
+	Rate       float64 `json:"rate" yaml:"rate"`             // The requests per second refilled into the token bucket.
+	Burst      int     `json:"burst" yaml:"burst"`           // The capacity of the token bucket, 1 by default.
+	Monthly    int     `json:"monthly" yaml:"monthly"`       // The requests granted by the data provider per billing period.
+	BillingDay int     `json:"billingDay" yaml:"billingDay"` // The day of month from 1 to 28 that the billing period starts, 1 by default.
+	WarnRatio  float64 `json:"warnRatio" yaml:"warnRatio"`   // The ratio of the monthly budget used to warn its exhaustion, 0.8 by default.
+	StateFile  string  `json:"stateFile" yaml:"stateFile"`   // The file to persist the usage of the billing period across restarts.
+}
+
+// Validate checks the request budget.
+func (rb *RequestBudget) Validate() error {
+	if rb.Rate < 0 || rb.Burst < 0 || rb.Monthly < 0 {
+		return fmt.Errorf("the request budget cannot be negative")
+	}
+	if rb.BillingDay < 0 || rb.BillingDay > 28 {
+		return fmt.Errorf("the billing day has to be from 1 to 28")
+	}
+	if rb.WarnRatio < 0 || rb.WarnRatio > 1 {
+		return fmt.Errorf("the warn ratio has to be from 0 to 1")
+	}
+	return nil
+}
+
+// HTTPConfig is the schema of the HTTP client settings of a plugin, it is applied by the plugins built on the common
+// client, and the zero values stand for the defaults of the Go HTTP client.
+type HTTPConfig struct {
+	Retries         int               `json:"retries" yaml:"retries"`                 // The retries of a request failed with a 5xx status or a timeout, the 4xx statuses are not retried.
+	RetryBackoff    int               `json:"retryBackoff" yaml:"retryBackoff"`       // The base backoff in milliseconds between the retries, it is doubled and jittered on each retry, 500 by default.
+	Proxy           string            `json:"proxy" yaml:"proxy"`                     // The URL of the HTTP(S) proxy, the proxy of the environment variables is taken if it is omitted.
+	CAFile          string            `json:"caFile" yaml:"caFile"`                   // The PEM encoded CA bundle trusted in addition to the system CAs.
+	CertFile        string            `json:"certFile" yaml:"certFile"`               // The PEM encoded client certificate.
+	KeyFile         string            `json:"keyFile" yaml:"keyFile"`                 // The PEM encoded private key of the client certificate.
+	Headers         map[string]string `json:"headers" yaml:"headers"`                 // The extra headers of the requests.
+	MaxResponseSize int64             `json:"maxResponseSize" yaml:"maxResponseSize"` // The maximum size in bytes of a response body.
+}
+
+// Validate checks the HTTP client settings.
+func (hc *HTTPConfig) Validate() error {
+	if hc.Retries < 0 || hc.RetryBackoff < 0 || hc.MaxResponseSize < 0 {
+		return fmt.Errorf("the http settings cannot be negative")
+	}
+	if (hc.CertFile == "") != (hc.KeyFile == "") {
+		return fmt.Errorf("the client certificate and its key are required to be set together")
+	}
+	if hc.Proxy != "" {
+		u, err := url.Parse(hc.Proxy)
+		if err != nil {
+			return fmt.Errorf("invalid proxy: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5" {
+			return fmt.Errorf("unsupported proxy scheme: %s", u.Scheme)
+		}
+	}
+	return nil
 }
 
 // Config is the resolved configuration of the oracle-server.
@@ -127,11 +305,16 @@ type Config struct {
 	VoteBuffer         uint64
 	Key                *keystore.Key
 	AutonityWSUrl      string
+	OracleContract     common.Address
 	PluginDIR          string
 	ProfileDir         string
 	ConfidenceStrategy int
 	PluginConfigs      map[string]PluginConfig
 	MetricConfigs      MetricConfig
+	LogConfigs         LogConfig
+	TraceConfigs       TraceConfig
+	IntegrityConfigs   IntegrityConfig
+	SnapshotConfigs    SnapshotConfig
 }
 
 func MakeConfig() *Config {
@@ -163,9 +346,16 @@ func MakeConfig() *Config {
 		os.Exit(1)
 	}
 
-	if config.MetricConfigs.EnableInfluxDB && config.MetricConfigs.EnableInfluxDBV2 {
+	if err = config.Validate(); err != nil {
+		log.SetFlags(0)
+		log.Println(err.Error())
+		os.Exit(1)
+	}
+
+	oracleContract, err := ResolveOracleContract(config.OracleContract)
+	if err != nil {
 		log.SetFlags(0)
-		log.Println("There are two metrics engine enabled, please select one: influxDB or influxDBV2")
+		log.Printf("invalid oracle contract address: %s", err.Error())
 		os.Exit(1)
 	}
 
@@ -180,6 +370,7 @@ func MakeConfig() *Config {
 		GasTipCap:          config.GasTipCap,
 		Key:                key,
 		AutonityWSUrl:      config.AutonityWSUrl,
+		OracleContract:     oracleContract,
 		PluginDIR:          config.PluginDIR,
 		ProfileDir:         config.ProfileDir,
 		LoggingLevel:       hclog.Level(config.LoggingLevel), //nolint
@@ -187,7 +378,138 @@ func MakeConfig() *Config {
 		ConfigFile:         oracleConfFile,
 		PluginConfigs:      pluginConfigs,
 		MetricConfigs:      config.MetricConfigs,
+		LogConfigs:         config.LogConfigs,
+		TraceConfigs:       config.TraceConfigs,
+		IntegrityConfigs:   config.IntegrityConfigs,
+		SnapshotConfigs:    config.SnapshotConfigs,
+	}
+}
+
+// Validate checks the configs of the oracle server which can be checked without the key store, the L1 connectivity
+// and the plugins.
+func (sc *ServerConfig) Validate() error {
+	if sc.MetricConfigs.EnableInfluxDB && sc.MetricConfigs.EnableInfluxDBV2 {
+		return fmt.Errorf("there are two metrics engine enabled, please select one: influxDB or influxDBV2")
+	}
+
+	if err := sc.LogConfigs.Validate(); err != nil {
+		return fmt.Errorf("invalid log configs: %w", err)
+	}
+
+	if err := sc.TraceConfigs.Validate(); err != nil {
+		return fmt.Errorf("invalid trace configs: %w", err)
+	}
+
+	if err := sc.IntegrityConfigs.Validate(); err != nil {
+		return fmt.Errorf("invalid integrity configs: %w", err)
+	}
+
+	if err := sc.SnapshotConfigs.Validate(); err != nil {
+		return fmt.Errorf("invalid snapshot configs: %w", err)
+	}
+
+	if _, err := ResolveOracleContract(sc.OracleContract); err != nil {
+		return fmt.Errorf("invalid oracle contract address: %w", err)
+	}
+
+	for _, c := range sc.PluginConfigs {
+		if err := c.Limits.Validate(); err != nil {
+			return fmt.Errorf("invalid resource limits of plugin %s: %w", c.Name, err)
+		}
+		if err := c.SymbolMap.Validate(); err != nil {
+			return fmt.Errorf("invalid symbol map of plugin %s: %w", c.Name, err)
+		}
+		if err := c.Budget.Validate(); err != nil {
+			return fmt.Errorf("invalid request budget of plugin %s: %w", c.Name, err)
+		}
+		if err := c.HTTP.Validate(); err != nil {
+			return fmt.Errorf("invalid http settings of plugin %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+const (
+	TraceExporterNone   = "none"
+	TraceExporterStdout = "stdout"
+	TraceExporterFile   = "file"
+)
+
+// Validate checks the exporters of the trace configs.
+func (tc *TraceConfig) Validate() error {
+	switch tc.Exporter {
+	case TraceExporterNone, TraceExporterStdout:
+	case TraceExporterFile:
+		if tc.File == "" {
+			return fmt.Errorf("the trace file is missing for the exporter: %s", TraceExporterFile)
+		}
+	default:
+		return fmt.Errorf("unknown trace exporter: %s, available exporters are: %s, %s, %s", tc.Exporter,
+			TraceExporterNone, TraceExporterStdout, TraceExporterFile)
+	}
+
+	if tc.EnableOTLP && tc.OTLPEndpoint == "" {
+		return fmt.Errorf("the OTLP endpoint is missing")
+	}
+	if tc.EnableOTLP {
+		u, err := url.Parse(tc.OTLPEndpoint)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("the OTLP endpoint is not a http or https URL: %s", tc.OTLPEndpoint)
+		}
+	}
+	return nil
+}
+
+// Validate checks the operator public key of the integrity configs.
+func (ic *IntegrityConfig) Validate() error {
+	_, err := ic.OperatorKey()
+	return err
+}
+
+// OperatorKey decodes the operator public key to verify the plugins' signatures, it returns nil if the key is not set.
+func (ic *IntegrityConfig) OperatorKey() (ed25519.PublicKey, error) {
+	if ic.PublicKey == "" {
+		return nil, nil
+	}
+
+	key, err := hex.DecodeString(strings.TrimPrefix(ic.PublicKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("the public key is not hex encoded: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("the public key has %d bytes, an ed25519 public key has %d bytes", len(key), ed25519.PublicKeySize)
+	}
+	return key, nil
+}
+
+// Validate checks the directory and the interval of the snapshot configs if the snapshots are enabled.
+func (sc *SnapshotConfig) Validate() error {
+	if !sc.Enable {
+		return nil
+	}
+	if sc.Dir == "" {
+		return fmt.Errorf("the snapshot directory is missing")
+	}
+	if sc.Interval <= 0 {
+		return fmt.Errorf("the snapshot interval must be positive")
+	}
+	if sc.Interval >= SampleTTL {
+		return fmt.Errorf("the snapshot interval must be shorter than the TTL of the samples %ds", SampleTTL)
+	}
+	return nil
+}
+
+// ResolveOracleContract resolves the oracle contract address, the one derived from the protocol deployer is taken if it
+// is not set.
+func ResolveOracleContract(address string) (common.Address, error) {
+	if address == "" {
+		return types.OracleContractAddress, nil
+	}
+
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("%s is not a hex address", address)
 	}
+	return common.HexToAddress(address), nil
 }
 
 func LoadKey(keyFile, password string) (*keystore.Key, error) {
@@ -262,4 +584,7 @@ func printUsage() {
 	fmt.Print("Usage of Autonity Oracle Server:\n")
 	fmt.Printf("%s <oracle_config.yml>\n", os.Args[0])
 	fmt.Print("Sub commands: \n  version: print the version of the oracle server.\n")
+	fmt.Print("  plugin probe <name>: launch a plugin from the plugin directory and probe its data fetching.\n")
+	fmt.Print("  plugin test <name>: run the conformance checks of a plugin against a local stand-in of its data source.\n")
+	fmt.Print("  validate-config <oracle_config.yml>: validate the configs, and the plugin configs against the plugins' schemas.\n")
 }
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"autonity-oracle/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-hclog"
 	"github.com/stretchr/testify/require"
 	"testing"
 )
@@ -21,6 +24,21 @@ func TestConfigs(t *testing.T) {
 	require.Equal(t, 5, len(pluginConfigs))
 }
 
+func TestResolveOracleContract(t *testing.T) {
+	config, err := LoadServerConfig("./config_for_test.yml")
+	require.NoError(t, err)
+	address, err := ResolveOracleContract(config.OracleContract)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x5a443704dd4B594B382c22a083e2BD3090A6feF3"), address)
+
+	address, err = ResolveOracleContract(DefaultConfig.OracleContract)
+	require.NoError(t, err)
+	require.Equal(t, types.OracleContractAddress, address)
+
+	_, err = ResolveOracleContract("0x1234")
+	require.Error(t, err)
+}
+
 func TestFormatVersion(t *testing.T) {
 	require.Equal(t, "v0.0.0", VersionString(0))
 	require.Equal(t, "v0.0.1", VersionString(1))
@@ -31,3 +49,149 @@ func TestFormatVersion(t *testing.T) {
 	require.Equal(t, "v1.2.5", VersionString(125))
 	require.Equal(t, "v2.5.5", VersionString(255))
 }
+
+func TestLogConfigs(t *testing.T) {
+	config, err := LoadServerConfig("./config_for_test.yml")
+	require.NoError(t, err)
+	require.NoError(t, config.LogConfigs.Validate())
+	require.Equal(t, LogFormatJSON, config.LogConfigs.Format)
+	require.Equal(t, LogOutputStdout, config.LogConfigs.Output)
+	require.Equal(t, DefaultLogConfig.MaxSize, config.LogConfigs.MaxSize)
+
+	defaultLevel := hclog.Level(config.LoggingLevel) //nolint
+	require.Equal(t, hclog.Debug, config.LogConfigs.ServerLogLevel(defaultLevel))
+	require.Equal(t, hclog.Trace, config.LogConfigs.PluginLogLevel("crypto_uniswap", defaultLevel))
+	require.Equal(t, defaultLevel, config.LogConfigs.PluginLogLevel("crypto_kraken", defaultLevel))
+
+	invalid := DefaultLogConfig
+	invalid.Format = "xml"
+	require.Error(t, invalid.Validate())
+
+	invalid = DefaultLogConfig
+	invalid.Output = LogOutputFile
+	invalid.File = ""
+	require.Error(t, invalid.Validate())
+}
+
+func TestIntegrityConfigs(t *testing.T) {
+	ic := DefaultIntegrityConfig
+	key, err := ic.OperatorKey()
+	require.NoError(t, err)
+	require.Nil(t, key)
+
+	ic.PublicKey = "0x3b6a27bcceb6a42d62a3a8d02a6f0d73653215771de243a63ac048a18b59da29"
+	key, err = ic.OperatorKey()
+	require.NoError(t, err)
+	require.Equal(t, 32, len(key))
+
+	ic.PublicKey = "3b6a27bcceb6a42d"
+	require.Error(t, ic.Validate())
+	ic.PublicKey = "not a hex key"
+	require.Error(t, ic.Validate())
+}
+
+func TestResourceLimits(t *testing.T) {
+	require.NoError(t, (&ResourceLimits{}).Validate())
+	require.NoError(t, (&ResourceLimits{MaxRSS: 512, MaxCPUTime: 3600, Cgroup: "/sys/fs/cgroup/oracle/plugin", CPUQuota: 0.5}).Validate())
+	require.Error(t, (&ResourceLimits{MaxRSS: -1}).Validate())
+	require.Error(t, (&ResourceLimits{CPUQuota: 0.5}).Validate())
+	require.Error(t, (&ResourceLimits{UID: 1000}).Validate())
+}
+
+func TestSymbolMap(t *testing.T) {
+	sm := SymbolMap{"EUR-USD": "EURUSD", "JPY-USD": "1/USDJPY"}
+	require.NoError(t, sm.Validate())
+	ticker, inverted, ok := sm.Ticker("JPY-USD")
+	require.True(t, ok)
+	require.True(t, inverted)
+	require.Equal(t, "USDJPY", ticker)
+	_, _, ok = sm.Ticker("GBP-USD")
+	require.False(t, ok)
+
+	require.Error(t, SymbolMap{"EURUSD": "EURUSD"}.Validate())
+	require.Error(t, SymbolMap{"EUR-USD": "1/"}.Validate())
+	require.Error(t, SymbolMap{"USD-JPY": "USDJPY", "JPY-USD": "1/USDJPY"}.Validate())
+}
+
+func TestRequestBudget(t *testing.T) {
+	require.NoError(t, (&RequestBudget{}).Validate())
+	require.NoError(t, (&RequestBudget{Rate: 0.5, Burst: 2, Monthly: 1000, BillingDay: 15, WarnRatio: 0.9}).Validate())
+	require.Error(t, (&RequestBudget{Monthly: -1}).Validate())
+	require.Error(t, (&RequestBudget{BillingDay: 31}).Validate())
+	require.Error(t, (&RequestBudget{WarnRatio: 1.5}).Validate())
+}
+
+func TestHTTPConfig(t *testing.T) {
+	require.NoError(t, (&HTTPConfig{}).Validate())
+	require.NoError(t, (&HTTPConfig{Retries: 3, Proxy: "http://proxy.local:3128", CertFile: "client.pem", KeyFile: "client.key"}).Validate())
+	require.Error(t, (&HTTPConfig{Retries: -1}).Validate())
+	require.Error(t, (&HTTPConfig{CertFile: "client.pem"}).Validate())
+	require.Error(t, (&HTTPConfig{Proxy: "ftp://proxy.local"}).Validate())
+}
+
+func TestSnapshotConfigs(t *testing.T) {
+	sc := DefaultSnapshotConfig
+	require.NoError(t, sc.Validate())
+	sc.Enable = true
+	require.NoError(t, sc.Validate())
+	sc.Interval = 0
+	require.Error(t, sc.Validate())
+	sc.Interval = SampleTTL
+	require.Error(t, sc.Validate())
+	sc = DefaultSnapshotConfig
+	sc.Enable = true
+	sc.Dir = ""
+	require.Error(t, sc.Validate())
+}
+
+func TestServerConfigValidate(t *testing.T) {
+	config, err := LoadServerConfig("./config_for_test.yml")
+	require.NoError(t, err)
+	require.NoError(t, config.Validate())
+
+	config.PluginConfigs = append(config.PluginConfigs, PluginConfig{Name: "test", Limits: ResourceLimits{MaxRSS: -1}})
+	require.ErrorContains(t, config.Validate(), "invalid resource limits of plugin test")
+
+	config.PluginConfigs[len(config.PluginConfigs)-1] = PluginConfig{Name: "test", SymbolMap: SymbolMap{"EUR-USD": ""}}
+	require.ErrorContains(t, config.Validate(), "invalid symbol map of plugin test")
+}
+
+func TestPluginConfigSchema(t *testing.T) {
+	schema := []types.ConfigField{
+		{Name: "key", Type: types.ConfigString, Required: true, Secret: true},
+		{Name: "timeout", Type: types.ConfigInt, Default: "10"},
+		{Name: "swapAddress", Type: types.ConfigAddress, Required: true, Default: "0x218F76e357594C82Cc29A88B90dd67b180827c88"},
+	}
+
+	t.Run("test valid config", func(t *testing.T) {
+		conf := PluginConfig{Name: "test", Key: "secret", Disabled: true}
+		warnings, err := conf.CheckSchema(schema)
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+		require.Equal(t, "******", conf.SchemaValue(schema[0]))
+		require.Equal(t, "10", conf.SchemaValue(schema[1]))
+	})
+
+	t.Run("test no schema", func(t *testing.T) {
+		conf := PluginConfig{Name: "test", Endpoint: "example.com"}
+		warnings, err := conf.CheckSchema(nil)
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+	})
+
+	t.Run("test invalid config", func(t *testing.T) {
+		conf := PluginConfig{Name: "test", SwapAddress: "0x1234", Endpoint: "example.com"}
+		warnings, err := conf.CheckSchema(schema)
+		require.ErrorIs(t, err, types.ErrInvalidPluginConfig)
+		require.ErrorContains(t, err, "the required field key is missing")
+		require.ErrorContains(t, err, "the field swapAddress is not a valid address: 0x1234")
+		require.Equal(t, []string{"the field endpoint is set but not used by the plugin"}, warnings)
+	})
+
+	t.Run("test invalid schema", func(t *testing.T) {
+		conf := PluginConfig{Name: "test"}
+		_, err := conf.CheckSchema([]types.ConfigField{{Name: "unknown"}, {Name: "timeout", Type: types.ConfigBool}})
+		require.ErrorContains(t, err, "the field unknown of the schema is unknown")
+		require.ErrorContains(t, err, "the field timeout is not of the type 2 in the schema")
+	})
+}
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"gopkg.in/natefinch/lumberjack.v2"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	LogFormatText   = "text"
+	LogFormatJSON   = "json"
+	LogOutputStdout = "stdout"
+	LogOutputFile   = "file"
+
+	// LogKeyRound and LogKeySymbol are the structured field names shared by all the log lines which carry a round ID
+	// or a symbol, thus the log lines can be filtered consistently across the server, plugin wrappers and plugins.
+	LogKeyRound  = "round"
+	LogKeySymbol = "symbol"
+)
+
+var (
+	logWriterOnce sync.Once
+	logWriter     io.Writer = os.Stdout
+)
+
+// Validate checks the format and the output of the log configs.
+func (lc *LogConfig) Validate() error {
+	if lc.Format != LogFormatText && lc.Format != LogFormatJSON {
+		return fmt.Errorf("unknown log format: %s, available formats are: %s, %s", lc.Format, LogFormatText, LogFormatJSON)
+	}
+
+	if lc.Output != LogOutputStdout && lc.Output != LogOutputFile {
+		return fmt.Errorf("unknown log output: %s, available outputs are: %s, %s", lc.Output, LogOutputStdout, LogOutputFile)
+	}
+
+	if lc.Output == LogOutputFile && lc.File == "" {
+		return fmt.Errorf("the log file is missing for the log output: %s", LogOutputFile)
+	}
+	return nil
+}
+
+// ServerLogLevel returns the logging verbosity of the oracle server, it takes the default level if it is not set.
+func (lc *LogConfig) ServerLogLevel(defaultLevel hclog.Level) hclog.Level {
+	if lc.ServerLevel != 0 {
+		return hclog.Level(lc.ServerLevel) //nolint
+	}
+	return defaultLevel
+}
+
+// PluginLogLevel returns the logging verbosity of a plugin by its name, it takes the level of the plugin wrappers if
+// there is no level set for the plugin, otherwise it takes the default level.
+func (lc *LogConfig) PluginLogLevel(name string, defaultLevel hclog.Level) hclog.Level {
+	if level, ok := lc.PluginLevels[name]; ok && level != 0 {
+		return hclog.Level(level) //nolint
+	}
+
+	if lc.PluginLevel != 0 {
+		return hclog.Level(lc.PluginLevel) //nolint
+	}
+	return defaultLevel
+}
+
+// NewLogger creates a named logger of a component with the level, the format and the output of the log configs.
+func NewLogger(lc *LogConfig, name string, level hclog.Level) hclog.Logger {
+	return hclog.New(&hclog.LoggerOptions{
+		Name:       name,
+		Output:     LogWriter(lc),
+		Level:      level,
+		JSONFormat: lc.Format == LogFormatJSON,
+	})
+}
+
+// LogWriter returns the process wide log writer, it is resolved once by the first log configs, thus all the loggers
+// write into the same rotated file if the output is file.
+func LogWriter(lc *LogConfig) io.Writer {
+	logWriterOnce.Do(func() {
+		if lc.Output != LogOutputFile {
+			return
+		}
+
+		rotator := newLogRotator(lc)
+		logWriter = rotator
+		if lc.RotateInterval > 0 {
+			go func() {
+				ticker := time.NewTicker(time.Duration(lc.RotateInterval) * time.Hour)
+				defer ticker.Stop()
+				for range ticker.C {
+					if err := rotator.Rotate(); err != nil {
+						fmt.Fprintf(os.Stderr, "cannot rotate log file: %s, err: %s\n", lc.File, err.Error())
+					}
+				}
+			}()
+		}
+	})
+	return logWriter
+}
+
+func newLogRotator(lc *LogConfig) *lumberjack.Logger {
+	return &lumberjack.Logger{
+		Filename:   lc.File,
+		MaxSize:    lc.MaxSize,
+		MaxAge:     lc.MaxAge,
+		MaxBackups: lc.MaxBackups,
+		LocalTime:  true,
+	}
+}
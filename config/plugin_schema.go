@@ -0,0 +1,117 @@
+package config
+
+import (
+	"autonity-oracle/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// serverSideFields are the plugin config fields consumed by the oracle server rather than by the plugin, thus they are
+// never reported as unused by a plugin.
+var serverSideFields = map[string]struct{}{
+	"name":          {},
+	"disabled":      {},
+	"checksum":      {},
+	"limits":        {},
+	"inProcess":     {},
+	"legacyConfEnv": {},
+}
+
+var configFieldKinds = map[types.ConfigFieldType]reflect.Kind{
+	types.ConfigString:  reflect.String,
+	types.ConfigInt:     reflect.Int,
+	types.ConfigBool:    reflect.Bool,
+	types.ConfigAddress: reflect.String,
+	types.ConfigMap:     reflect.Map,
+	types.ConfigObject:  reflect.Struct,
+}
+
+// fields returns the values of the plugin config fields by their yaml keys.
+func (pc *PluginConfig) fields() map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	v := reflect.ValueOf(pc).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		key := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key != "" && key != "-" {
+			fields[key] = v.Field(i)
+		}
+	}
+	return fields
+}
+
+// CheckSchema validates the plugin config against the config schema published by the plugin, it returns the warnings
+// of the fields that are set but not used by the plugin, and an error listing all the problems of the config. An empty
+// schema is not checked, as the plugin does not publish one.
+func (pc *PluginConfig) CheckSchema(schema []types.ConfigField) ([]string, error) {
+	if len(schema) == 0 {
+		return nil, nil
+	}
+
+	fields := pc.fields()
+	used := make(map[string]struct{})
+	var problems []string
+	for _, f := range schema {
+		used[f.Name] = struct{}{}
+		v, ok := fields[f.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("the field %s of the schema is unknown", f.Name))
+			continue
+		}
+		if kind, ok := configFieldKinds[f.Type]; !ok || kind != v.Kind() {
+			problems = append(problems, fmt.Sprintf("the field %s is not of the type %d in the schema", f.Name, f.Type))
+			continue
+		}
+
+		value := f.Default
+		if !v.IsZero() {
+			value = fmt.Sprint(v.Interface())
+		}
+		switch {
+		case value == "":
+			if f.Required {
+				problems = append(problems, fmt.Sprintf("the required field %s is missing", f.Name))
+			}
+		case f.Type == types.ConfigAddress && !common.IsHexAddress(value):
+			problems = append(problems, fmt.Sprintf("the field %s is not a valid address: %s", f.Name, value))
+		case f.Type == types.ConfigInt:
+			if n, err := strconv.Atoi(value); err != nil || n < 0 {
+				problems = append(problems, fmt.Sprintf("the field %s is not a non-negative integer: %s", f.Name, value))
+			}
+		}
+	}
+
+	var warnings []string
+	for key, v := range fields {
+		if _, ok := used[key]; ok {
+			continue
+		}
+		if _, ok := serverSideFields[key]; ok || v.IsZero() {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf("the field %s is set but not used by the plugin", key))
+	}
+	sort.Strings(warnings)
+
+	if len(problems) != 0 {
+		return warnings, fmt.Errorf("%w: %s", types.ErrInvalidPluginConfig, strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
+
+// SchemaValue returns the value of a plugin config field of the schema to be printed, the default value is taken if
+// the field is omitted, and the secrets are masked.
+func (pc *PluginConfig) SchemaValue(f types.ConfigField) string {
+	v, ok := pc.fields()[f.Name]
+	value := f.Default
+	if ok && !v.IsZero() {
+		value = fmt.Sprint(v.Interface())
+	}
+	if f.Secret && value != "" {
+		return "******"
+	}
+	return value
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InvertedTickerPrefix marks a ticker of the symbol map whose price is the reciprocal of the protocol symbol's price,
+// for example, JPY-USD: 1/USDJPY.
+const InvertedTickerPrefix = "1/"
+
+// SymbolMap maps the protocol symbols in the `-` separated style, for example EUR-USD, to the tickers of the data
+// provider. The mapped symbols take precedence over the separator heuristic of the plugins.
+type SymbolMap map[string]string
+
+// Ticker returns the ticker of the data provider mapped from the protocol symbol, and if its price is inverted.
+func (sm SymbolMap) Ticker(symbol string) (string, bool, bool) {
+	t, ok := sm[symbol]
+	if !ok {
+		return "", false, false
+	}
+	ticker, inverted := ParseTicker(t)
+	return ticker, inverted, true
+}
+
+// ParseTicker parses a ticker of the symbol map, it returns the ticker without the inverted prefix, and if it is
+// inverted.
+func ParseTicker(t string) (string, bool) {
+	if strings.HasPrefix(t, InvertedTickerPrefix) {
+		return strings.TrimPrefix(t, InvertedTickerPrefix), true
+	}
+	return t, false
+}
+
+// Validate checks the symbol map, the protocol symbols are `-` separated pairs, and a ticker is mapped by one symbol
+// only, as the prices of the data provider are mapped back to the protocol symbols by the tickers.
+func (sm SymbolMap) Validate() error {
+	symbols := make([]string, 0, len(sm))
+	for symbol := range sm {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	mapped := make(map[string]string)
+	for _, symbol := range symbols {
+		parts := strings.Split(symbol, "-")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return fmt.Errorf("the symbol %s is not a `-` separated pair", symbol)
+		}
+		ticker, _ := ParseTicker(sm[symbol])
+		if strings.TrimSpace(ticker) == "" {
+			return fmt.Errorf("the ticker of symbol %s is empty", symbol)
+		}
+		if other, ok := mapped[ticker]; ok {
+			return fmt.Errorf("the ticker %s is mapped by both %s and %s", ticker, other, symbol)
+		}
+		mapped[ticker] = symbol
+	}
+	return nil
+}
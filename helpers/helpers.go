@@ -100,14 +100,80 @@ func Median(prices []decimal.Decimal) (decimal.Decimal, error) {
 	return prices[l/2], nil
 }
 
+// WeightedMedian returns the weighted median of the prices, that is the price at which the cumulative weight of the
+// sorted prices reaches the half of the total weight, and the two prices at the half are averaged. It equals to Median
+// with the equal weights. The input slices are not reordered.
+func WeightedMedian(prices []decimal.Decimal, weights []decimal.Decimal) (decimal.Decimal, error) {
+	if len(prices) == 0 || len(prices) != len(weights) {
+		return decimal.Decimal{}, fmt.Errorf("prices and weights must be of the same non-zero length")
+	}
+
+	indexes := make([]int, len(prices))
+	total := decimal.Zero
+	for i, w := range weights {
+		if w.IsNegative() {
+			return decimal.Decimal{}, fmt.Errorf("weights cannot be negative")
+		}
+		indexes[i] = i
+		total = total.Add(w)
+	}
+	if total.IsZero() {
+		return decimal.Decimal{}, fmt.Errorf("total weight cannot be zero")
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return prices[indexes[i]].Cmp(prices[indexes[j]]) == -1
+	})
+
+	half := total.Div(decimal.NewFromInt(2))
+	cumulative := decimal.Zero
+	for k, i := range indexes {
+		if weights[i].IsZero() {
+			continue
+		}
+		cumulative = cumulative.Add(weights[i])
+		if cumulative.LessThan(half) {
+			continue
+		}
+		if cumulative.GreaterThan(half) {
+			return prices[i], nil
+		}
+		// the cumulative weight is exactly at the half, average it with the next weighted price.
+		for _, next := range indexes[k+1:] {
+			if !weights[next].IsZero() {
+				return prices[i].Add(prices[next]).Div(decimal.NewFromInt(2)), nil
+			}
+		}
+		return prices[i], nil
+	}
+	return prices[indexes[len(indexes)-1]], nil
+}
+
 // VWAP computes the volume weighted average price for the input prices with their corresponding volumes
 func VWAP(prices []decimal.Decimal, volumes []*big.Int) (decimal.Decimal, *big.Int, error) {
+	return WeightedVWAP(prices, volumes, nil)
+}
+
+// WeightedVWAP computes the VWAP with the volumes being scaled by the weights, the weights are applied in decimal, thus
+// a small volume is not truncated to zero. The volumes are taken as they are if the weights are nil. It returns the
+// highest volume before the weighting.
+func WeightedVWAP(prices []decimal.Decimal, volumes []*big.Int, weights []decimal.Decimal) (decimal.Decimal, *big.Int, error) {
 	if len(prices) == 0 || len(volumes) == 0 || len(prices) != len(volumes) {
 		return decimal.Zero, nil, errors.New("prices and volumes must be of the same non-zero length")
 	}
 
+	if weights != nil && len(weights) != len(volumes) {
+		return decimal.Zero, nil, errors.New("weights and volumes must be of the same length")
+	}
+
+	for _, v := range volumes {
+		if v == nil {
+			return decimal.Zero, nil, errors.New("volumes cannot be nil")
+		}
+	}
+
 	var totalWeightedPrice decimal.Decimal
-	totalVolume := big.NewInt(0)
+	totalVolume := decimal.Zero
 	highestVol := new(big.Int).Set(volumes[0])
 
 	for i := range prices {
@@ -117,22 +183,25 @@ func VWAP(prices []decimal.Decimal, volumes []*big.Int) (decimal.Decimal, *big.I
 
 		// Convert volume to decimal.Decimal
 		volumeDecimal := decimal.NewFromBigInt(volumes[i], 0)
+		if weights != nil {
+			volumeDecimal = volumeDecimal.Mul(weights[i])
+		}
 
 		// Calculate weighted price for current price and volume
 		weightedPrice := prices[i].Mul(volumeDecimal) // Use decimal.Decimal for precision
 		totalWeightedPrice = totalWeightedPrice.Add(weightedPrice)
 
 		// Accumulate total volume
-		totalVolume.Add(totalVolume, volumes[i])
+		totalVolume = totalVolume.Add(volumeDecimal)
 	}
 
 	// Avoid division by zero
-	if totalVolume.Cmp(big.NewInt(0)) == 0 {
+	if totalVolume.IsZero() {
 		return decimal.Zero, nil, errors.New("total volume cannot be zero")
 	}
 
 	// Calculate VWAP
-	vwap := totalWeightedPrice.Div(decimal.NewFromBigInt(totalVolume, 0))
+	vwap := totalWeightedPrice.Div(totalVolume)
 	return vwap, highestVol, nil
 }
 
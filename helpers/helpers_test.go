@@ -73,6 +73,31 @@ func TestMedian(t *testing.T) {
 	})
 }
 
+func TestWeightedMedian(t *testing.T) {
+	one := decimal.NewFromInt(1)
+	prices := []decimal.Decimal{decimal.RequireFromString("1.2"), decimal.RequireFromString("1.0"),
+		decimal.RequireFromString("1.1"), decimal.RequireFromString("1.3")}
+
+	// it equals to the median with the equal weights.
+	median, err := WeightedMedian(prices, []decimal.Decimal{one, one, one, one})
+	require.NoError(t, err)
+	require.Equal(t, "1.15", median.String())
+	median, err = WeightedMedian(prices[:3], []decimal.Decimal{one, one, one})
+	require.NoError(t, err)
+	require.Equal(t, "1.1", median.String())
+	require.Equal(t, "1.2", prices[0].String())
+
+	// a down-weighted price moves the median less.
+	median, err = WeightedMedian(prices[:2], []decimal.Decimal{decimal.NewFromFloat(0.3), one})
+	require.NoError(t, err)
+	require.Equal(t, "1", median.String())
+
+	_, err = WeightedMedian(prices, []decimal.Decimal{one})
+	require.Error(t, err)
+	_, err = WeightedMedian(prices[:1], []decimal.Decimal{decimal.Zero})
+	require.Error(t, err)
+}
+
 func TestVWAP(t *testing.T) {
 	tests := []struct {
 		prices             []decimal.Decimal
@@ -127,6 +152,19 @@ func TestVWAP(t *testing.T) {
 			expectedHighestVol: nil,
 			expectError:        true,
 		},
+		{
+			prices: []decimal.Decimal{
+				decimal.NewFromFloat(100.0),
+				decimal.NewFromFloat(200.0),
+			},
+			volumes: []*big.Int{
+				big.NewInt(10),
+				nil,
+			},
+			expectedVWAP:       decimal.Zero,
+			expectedHighestVol: nil,
+			expectError:        true,
+		},
 	}
 
 	for _, test := range tests {
@@ -153,3 +191,20 @@ func TestVWAP(t *testing.T) {
 		}
 	}
 }
+
+func TestWeightedVWAP(t *testing.T) {
+	prices := []decimal.Decimal{decimal.NewFromInt(100), decimal.NewFromInt(200)}
+	volumes := []*big.Int{big.NewInt(1), big.NewInt(1)}
+
+	// the weight of a small volume is not truncated to zero.
+	vwap, highestVol, err := WeightedVWAP(prices, volumes, []decimal.Decimal{decimal.NewFromFloat(0.5), decimal.NewFromInt(1)})
+	require.NoError(t, err)
+	require.Equal(t, "166.6666666666666667", vwap.String())
+	require.Equal(t, big.NewInt(1), highestVol)
+
+	_, _, err = WeightedVWAP(prices, volumes, []decimal.Decimal{decimal.NewFromInt(1)})
+	require.Error(t, err)
+
+	_, _, err = WeightedVWAP(prices, volumes, []decimal.Decimal{decimal.Zero, decimal.Zero})
+	require.Error(t, err)
+}
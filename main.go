@@ -5,7 +5,11 @@ import (
 	contract "autonity-oracle/contract_binder/contract"
 	"autonity-oracle/monitor"
 	"autonity-oracle/oracle_server"
+	"autonity-oracle/plugin_probe"
+	_ "autonity-oracle/plugins/builtin" // link the plugins which can run in process.
+	"autonity-oracle/tracing"
 	"autonity-oracle/types"
+	"context"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/metrics/influxdb"
 	"log"
@@ -15,10 +19,29 @@ import (
 )
 
 func main() { //nolint
+	// run the plugin sub commands without the keystore and the L1 connectivity.
+	if len(os.Args) > 1 && os.Args[1] == "plugin" {
+		os.Exit(pluginprobe.Run(os.Args[2:], os.Stdout))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "validate-config" {
+		os.Exit(pluginprobe.ValidateConfig(os.Args[2:], os.Stdout))
+	}
+
 	conf := config.MakeConfig()
 	log.Printf("\n\n\n \tRunning autonity oracle server %s\n\twith plugin directory: %s\n "+
 		"\tby connecting to L1 node: %s\n \ton oracle contract address: %s \n\n\n",
-		config.VersionString(config.Version), conf.PluginDIR, conf.AutonityWSUrl, types.OracleContractAddress)
+		config.VersionString(config.Version), conf.PluginDIR, conf.AutonityWSUrl, conf.OracleContract)
+
+	shutdownTracing, err := tracing.Setup(&conf.TraceConfigs, config.VersionString(config.Version))
+	if err != nil {
+		log.Printf("cannot setup tracing: %s", err.Error())
+		os.Exit(1)
+	}
+	defer func() {
+		if e := shutdownTracing(context.Background()); e != nil {
+			log.Printf("cannot flush traces: %s", e.Error())
+		}
+	}()
 
 	dialer := &types.L1Dialer{}
 	client, err := dialer.Dial(conf.AutonityWSUrl)
@@ -27,12 +50,18 @@ func main() { //nolint
 		os.Exit(1)
 	}
 
-	oc, err := contract.NewOracle(types.OracleContractAddress, client)
+	oc, err := contract.NewOracle(conf.OracleContract, client)
 	if err != nil {
 		log.Printf("cannot bind to oracle contract in Autonity network via web socket: %s", err.Error())
 		os.Exit(1)
 	}
 
+	if err = oracleserver.ProbeOracleContract(client, oc, conf.OracleContract); err != nil {
+		log.Printf("the oracle contract at %s is incompatible with this oracle server %s: %s",
+			conf.OracleContract, config.VersionString(config.Version), err.Error())
+		os.Exit(1)
+	}
+
 	oracle := oracleserver.NewOracleServer(conf, dialer, client, oc)
 	go oracle.Start()
 	defer oracle.Stop()
@@ -0,0 +1,62 @@
+package oracleserver
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/types"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var errNoContractCode = errors.New("there is no contract deployed at the oracle contract address")
+
+// ProbeOracleContract checks if the oracle contract deployed at the address is compatible with the binding in
+// contract_binder. The view methods consumed by the oracle server are called through the binding, thus a missing method
+// or a mismatched return type fails the probe, it works for the proxy deployments as well, as the calls are delegated.
+// The decimals of the contract must match with the one that the reports are built with.
+func ProbeOracleContract(client types.Blockchain, oc contract.ContractAPI, address common.Address) error {
+	code, err := client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return fmt.Errorf("cannot get the oracle contract code: %w", err)
+	}
+
+	if len(code) == 0 {
+		return fmt.Errorf("%w: %s", errNoContractCode, address)
+	}
+
+	symbols, err := oc.GetSymbols(nil)
+	if err != nil {
+		return fmt.Errorf("cannot call getSymbols of the oracle contract: %w", err)
+	}
+
+	round, err := oc.GetRound(nil)
+	if err != nil {
+		return fmt.Errorf("cannot call getRound of the oracle contract: %w", err)
+	}
+
+	if _, err = oc.GetVotePeriod(nil); err != nil {
+		return fmt.Errorf("cannot call getVotePeriod of the oracle contract: %w", err)
+	}
+
+	if _, err = oc.GetVoters(nil); err != nil {
+		return fmt.Errorf("cannot call getVoters of the oracle contract: %w", err)
+	}
+
+	// the round data of a symbol is decoded into the binding's struct, it checks the layout of the round data.
+	if len(symbols) > 0 {
+		if _, err = oc.GetRoundData(nil, round, symbols[0]); err != nil {
+			return fmt.Errorf("cannot call getRoundData of the oracle contract: %w", err)
+		}
+	}
+
+	decimals, err := oc.GetDecimals(nil)
+	if err != nil {
+		return fmt.Errorf("cannot get the decimals of the oracle contract: %w", err)
+	}
+
+	if decimals != OracleDecimals {
+		return fmt.Errorf("the oracle contract decimals %d mismatches with the expected decimals %d", decimals, OracleDecimals)
+	}
+	return nil
+}
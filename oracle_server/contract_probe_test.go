@@ -0,0 +1,83 @@
+package oracleserver
+
+import (
+	contract "autonity-oracle/contract_binder/contract"
+	cMock "autonity-oracle/contract_binder/contract/mock"
+	"autonity-oracle/types"
+	"autonity-oracle/types/mock"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+func TestProbeOracleContract(t *testing.T) {
+	// a proxy's runtime bytecode does not embed the selectors of the oracle contract.
+	code := []byte{0x60, 0x80, 0x60, 0x40, 0x52}
+	round := big.NewInt(10)
+
+	expectViews := func(contractMock *cMock.MockContractAPI) {
+		contractMock.EXPECT().GetSymbols(nil).Return([]string{"NTN-USD"}, nil)
+		contractMock.EXPECT().GetRound(nil).Return(round, nil)
+		contractMock.EXPECT().GetVotePeriod(nil).Return(big.NewInt(30), nil)
+		contractMock.EXPECT().GetVoters(nil).Return([]common.Address{{}}, nil)
+		contractMock.EXPECT().GetRoundData(nil, round, "NTN-USD").Return(contract.IOracleRoundData{}, nil)
+	}
+
+	t.Run("compatible contract", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		l1Mock.EXPECT().CodeAt(gomock.Any(), types.OracleContractAddress, nil).Return(code, nil)
+		expectViews(contractMock)
+		contractMock.EXPECT().GetDecimals(nil).Return(OracleDecimals, nil)
+		require.NoError(t, ProbeOracleContract(l1Mock, contractMock, types.OracleContractAddress))
+	})
+
+	t.Run("no contract code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		l1Mock.EXPECT().CodeAt(gomock.Any(), types.OracleContractAddress, nil).Return(nil, nil)
+		err := ProbeOracleContract(l1Mock, contractMock, types.OracleContractAddress)
+		require.ErrorIs(t, err, errNoContractCode)
+	})
+
+	t.Run("missing method", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		l1Mock.EXPECT().CodeAt(gomock.Any(), types.OracleContractAddress, nil).Return(code, nil)
+		contractMock.EXPECT().GetSymbols(nil).Return([]string{"NTN-USD"}, nil)
+		contractMock.EXPECT().GetRound(nil).Return(nil, errors.New("execution reverted"))
+		err := ProbeOracleContract(l1Mock, contractMock, types.OracleContractAddress)
+		require.ErrorContains(t, err, "getRound")
+	})
+
+	t.Run("mismatched decimals", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		l1Mock.EXPECT().CodeAt(gomock.Any(), types.OracleContractAddress, nil).Return(code, nil)
+		expectViews(contractMock)
+		contractMock.EXPECT().GetDecimals(nil).Return(uint8(8), nil)
+		require.Error(t, ProbeOracleContract(l1Mock, contractMock, types.OracleContractAddress))
+	})
+
+	t.Run("decimals call failure", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		l1Mock.EXPECT().CodeAt(gomock.Any(), types.OracleContractAddress, nil).Return(code, nil)
+		expectViews(contractMock)
+		contractMock.EXPECT().GetDecimals(nil).Return(uint8(0), errors.New("execution reverted"))
+		require.ErrorContains(t, ProbeOracleContract(l1Mock, contractMock, types.OracleContractAddress), "execution reverted")
+	})
+}
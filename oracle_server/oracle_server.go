@@ -6,12 +6,15 @@ import (
 	"autonity-oracle/helpers"
 	pWrapper "autonity-oracle/plugin_wrapper"
 	common2 "autonity-oracle/plugins/common"
+	"autonity-oracle/tracing"
 	"autonity-oracle/types"
 	"context"
 	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	tp "github.com/ethereum/go-ethereum/core/types"
@@ -21,11 +24,14 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/modern-go/reflect2"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"io/fs"
 	"math"
 	"math/big"
 	o "os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -45,6 +51,8 @@ var (
 	invalidSalt     = big.NewInt(0)
 	tenSecsInterval = 10 * time.Second // ticker to check L2 connectivity and gc round data.
 	oneSecsInterval = 1 * time.Second  // sampling interval during data pre-sampling period.
+	txTraceTimeout  = 5 * time.Minute  // the max duration to trace the inclusion of a vote tx.
+	backfillBudget  = 3 * time.Second  // the max duration of the backfill of the samples before a vote.
 
 	bridgerSymbols = []string{"ATN-USDC", "NTN-USDC", "USDC-USD"} // used for value bridging to USD by USDC
 
@@ -54,6 +62,7 @@ var (
 	l1ConnectivityErrs = metrics.GetOrRegisterCounter("oracle/l1/errs", nil)
 	accountBalance     = metrics.GetOrRegisterGauge("oracle/balance", nil)
 	isVoterFlag        = metrics.GetOrRegisterGauge("oracle/isVoter", nil)
+	skippedReveals     = metrics.GetOrRegisterCounter("oracle/reveal/skipped", nil)
 )
 
 const (
@@ -134,13 +143,17 @@ type OracleServer struct {
 	conf   *config.Config
 
 	doneCh        chan struct{}
+	ctx           context.Context // ctx is cancelled on Stop, it ends the background routines of the server.
+	cancel        context.CancelFunc
 	regularTicker *time.Ticker // the clock source to trigger the 10s interval job.
 	psTicker      *time.Ticker // the pre-sampling ticker in 1s.
 
 	runningPlugins  map[string]*pWrapper.PluginWrapper // the plugin clients that connect with different adapters.
 	samplingSymbols []string                           // the symbols for data fetching in oracle service, can be different from the required protocol symbols.
 
-	keyRequiredPlugins map[string]struct{} // saving those plugins which require a key granted by data provider
+	keyRequiredPlugins map[string]struct{}           // saving those plugins which require a key granted by data provider
+	invalidConfs       map[string]invalidConf        // the plugins whose config does not match their config schema.
+	supervisions       map[string]*pluginSupervision // the failures and restarts of the plugins whose process exited.
 
 	// the reporting staffs
 	dialer         types.Dialer
@@ -155,6 +168,11 @@ type OracleServer struct {
 	protocolSymbols []string //symbols required for the voting on the oracle contract protocol.
 	pricePrecision  decimal.Decimal
 	roundData       map[uint64]*types.RoundData
+	pluginPrices    map[string]map[string]decimal.Decimal // the prices of per plugin by symbol of the round under aggregation.
+
+	// symbolTransitions schedules the upcoming protocol symbols by their activation round, the upcoming symbols are
+	// sampled ahead of time, while the reports keep the current protocol symbols until the activation round.
+	symbolTransitions map[uint64][]string
 
 	chPenalizedEvent  chan *contract.OraclePenalized
 	subPenalizedEvent event.Subscription
@@ -169,34 +187,47 @@ type OracleServer struct {
 	sampleEventFeed        event.Feed
 	lostSync               bool // set to true if the connectivity with L1 Autonity network is dropped during runtime.
 	commitmentHashComputer *CommitmentHashComputer
+	oracleABI              *abi.ABI // the oracle ABI to pack the vote simulation and to decode its revert reason.
 
 	serverMemories *ServerMemories // server memories to be flushed.
 
+	refusedBinaries map[string]refusedBinary // the plugin binaries that failed the integrity verification.
+	lastSnapshotAt  time.Time                // the time of the last snapshot of the plugins' samples.
+
 	fsWatcher *fsnotify.Watcher // FS watcher watches the changes of plugins and the plugins' configs.
 	chainID   int64             // ChainID saves the L1 chain ID, it is used for plugin compatibility check.
+
+	roundCtx  context.Context // roundCtx carries the span of the current round, the stages of the round are traced as its children.
+	roundSpan trace.Span      // roundSpan lasts from the round event to the next one.
 }
 
 func NewOracleServer(conf *config.Config, dialer types.Dialer, client types.Blockchain,
 	oc contract.ContractAPI) *OracleServer {
+	ctx, cancel := context.WithCancel(context.Background())
 	os := &OracleServer{
+		ctx:                ctx,
+		cancel:             cancel,
 		conf:               conf,
 		dialer:             dialer,
 		client:             client,
 		oracleContract:     oc,
 		roundData:          make(map[uint64]*types.RoundData),
+		symbolTransitions:  make(map[uint64][]string),
 		runningPlugins:     make(map[string]*pWrapper.PluginWrapper),
 		keyRequiredPlugins: make(map[string]struct{}),
+		invalidConfs:       make(map[string]invalidConf),
+		supervisions:       make(map[string]*pluginSupervision),
+		refusedBinaries:    make(map[string]refusedBinary),
 		doneCh:             make(chan struct{}),
 		regularTicker:      time.NewTicker(tenSecsInterval),
 		psTicker:           time.NewTicker(oneSecsInterval),
 		pricePrecision:     decimal.NewFromBigInt(common.Big1, int32(OracleDecimals)),
+		roundCtx:           context.Background(),
+		roundSpan:          trace.SpanFromContext(context.Background()),
 	}
 
-	os.logger = hclog.New(&hclog.LoggerOptions{
-		Name:   reflect2.TypeOfPtr(os).String() + conf.Key.Address.String(),
-		Output: o.Stdout,
-		Level:  conf.LoggingLevel,
-	})
+	os.logger = config.NewLogger(&conf.LogConfigs, reflect2.TypeOfPtr(os).String()+conf.Key.Address.String(),
+		conf.LogConfigs.ServerLogLevel(conf.LoggingLevel))
 
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
@@ -213,6 +244,13 @@ func NewOracleServer(conf *config.Config, dialer types.Dialer, client types.Bloc
 	}
 	os.commitmentHashComputer = commitmentHashComputer
 
+	oracleABI, err := contract.OracleMetaData.GetAbi()
+	if err != nil {
+		os.logger.Error("cannot parse oracle contract ABI", "err", err)
+		o.Exit(1)
+	}
+	os.oracleABI = oracleABI
+
 	// load historic state, otherwise default initial state will be used.
 	state := &ServerMemories{}
 	err = state.loadState(os.conf.ProfileDir)
@@ -223,7 +261,7 @@ func NewOracleServer(conf *config.Config, dialer types.Dialer, client types.Bloc
 
 	// discover plugins from plugin dir at startup.
 	binaries, err := helpers.ListPlugins(conf.PluginDIR)
-	if len(binaries) == 0 || err != nil {
+	if (len(binaries) == 0 && !hasInProcessPlugins(conf.PluginConfigs)) || err != nil {
 		// to stop the service on the start once there is no plugin in the db.
 		os.logger.Error("no plugin discovered", "plugin-dir", os.conf.PluginDIR)
 		o.Exit(1)
@@ -233,11 +271,12 @@ func NewOracleServer(conf *config.Config, dialer types.Dialer, client types.Bloc
 	for _, file := range binaries {
 		f := file
 		pConf := conf.PluginConfigs[f.Name()]
-		if pConf.Disabled {
+		if pConf.Disabled || runsInProcess(f.Name(), &pConf) {
 			continue
 		}
 		os.tryToLaunchPlugin(f, pConf)
 	}
+	os.launchInProcessPlugins(conf.PluginConfigs)
 
 	os.logger.Info("running oracle contract listener at", "WS", conf.AutonityWSUrl, "ID", conf.Key.Address.String())
 	err = os.syncStates()
@@ -282,9 +321,9 @@ func (os *OracleServer) syncStates() error {
 		return err
 	}
 
-	os.logger.Info("syncStates", "CurrentRound", os.curRound, "Num of AvailableSymbols", len(os.protocolSymbols), "CurrentSymbols", os.protocolSymbols)
+	os.logger.Info("syncStates", config.LogKeyRound, os.curRound, "Num of AvailableSymbols", len(os.protocolSymbols), "CurrentSymbols", os.protocolSymbols)
 	os.AddNewSymbols(os.protocolSymbols)
-	os.logger.Info("syncStates", "CurrentRound", os.curRound, "Num of bridgerSymbols", len(bridgerSymbols), "bridgerSymbols", bridgerSymbols)
+	os.logger.Info("syncStates", config.LogKeyRound, os.curRound, "Num of bridgerSymbols", len(bridgerSymbols), "bridgerSymbols", bridgerSymbols)
 	os.AddNewSymbols(bridgerSymbols)
 
 	// subscribe on-chain round rotation event
@@ -409,8 +448,9 @@ func (os *OracleServer) printLatestRoundData(newRound uint64) {
 			return
 		}
 
-		os.logger.Debug("get round price", "round", newRound-1, "symbol", s, "Price",
+		os.logger.Debug("get round price", config.LogKeyRound, newRound-1, config.LogKeySymbol, s, "Price",
 			rd.Price.String(), "success", rd.Success)
+		os.observeOnChainMedian(newRound-1, s, rd)
 	}
 
 	for _, s := range os.protocolSymbols {
@@ -425,7 +465,7 @@ func (os *OracleServer) printLatestRoundData(newRound uint64) {
 			continue
 		}
 
-		os.logger.Debug("latest round price", "round", rd.Round.Uint64(), "symbol", s, "price",
+		os.logger.Debug("latest round price", config.LogKeyRound, rd.Round.Uint64(), config.LogKeySymbol, s, "price",
 			price.Div(os.pricePrecision).String(), "success", rd.Success)
 	}
 }
@@ -450,12 +490,18 @@ func (os *OracleServer) handlePreSampling(preSampleTS int64) error {
 
 	// do the data pre-sampling.
 	os.logger.Debug("data pre-sampling", "on height", curHeight, "TS", preSampleTS)
-	os.samplePrice(os.samplingSymbols, preSampleTS)
+	_, span := tracing.Start(os.roundCtx, "pre-sampling", tracing.AttrRound.Int64(int64(os.curRound+1)), //nolint
+		attribute.Int64("ts", preSampleTS), attribute.Int64("height", int64(curHeight))) //nolint
+	os.samplePrice(span.SpanContext(), os.samplingSymbols, preSampleTS)
+	span.End()
 
 	return nil
 }
 
-func (os *OracleServer) handleRoundVote() error {
+func (os *OracleServer) handleRoundVote() (err error) {
+	ctx, span := tracing.Start(os.roundCtx, "round vote", tracing.AttrRound.Int64(int64(os.curRound))) //nolint
+	defer func() { tracing.EndWithError(span, err) }()
+
 	// if the autonity node is on peer synchronization state, just skip the reporting.
 	syncing, err := os.client.SyncProgress(context.Background())
 	if err != nil {
@@ -469,11 +515,12 @@ func (os *OracleServer) handleRoundVote() error {
 	}
 
 	// get latest symbols from oracle.
-	os.protocolSymbols, err = os.oracleContract.GetSymbols(nil)
+	symbols, err := os.oracleContract.GetSymbols(nil)
 	if err != nil {
 		os.logger.Error("handleRoundVote get symbols", "error", err.Error())
 		return err
 	}
+	os.updateProtocolSymbols(symbols)
 
 	os.printLatestRoundData(os.curRound)
 
@@ -490,6 +537,12 @@ func (os *OracleServer) handleRoundVote() error {
 		os.logger.Debug("no last round data, client is no longer a voter or it will report with commitment hash")
 	}
 
+	// the last round data can be revealed only if its commitment was included on chain, otherwise the reveal does not
+	// match any commitment, thus the vote is downgraded to a commit-only one.
+	if ok && !lastRoundData.MissingData && !os.commitmentIncluded(lastRoundData) {
+		lastRoundData, ok = nil, false
+	}
+
 	// if node is no longer a validator, and it doesn't have last round data, skip reporting.
 	if !isVoter && !ok {
 		os.logger.Debug("skip data reporting since client is no longer a voter")
@@ -513,15 +566,15 @@ func (os *OracleServer) handleRoundVote() error {
 			isVoterFlag.Update(1)
 		}
 		// report with last round data and with current round commitment hash.
-		return os.reportWithCommitment(os.curRound, lastRoundData)
+		return os.reportWithCommitment(ctx, os.curRound, lastRoundData)
 	}
 
 	// voter reports with last round data but without current round commitment since it is not committee member now.
-	return os.reportWithoutCommitment(lastRoundData)
+	return os.reportWithoutCommitment(ctx, lastRoundData)
 }
 
-func (os *OracleServer) reportWithCommitment(newRound uint64, lastRoundData *types.RoundData) error {
-	curRoundData, err := os.buildRoundData(newRound)
+func (os *OracleServer) reportWithCommitment(ctx context.Context, newRound uint64, lastRoundData *types.RoundData) error {
+	curRoundData, err := os.buildRoundData(ctx, newRound)
 	if err != nil {
 		os.logger.Error("build round data", "error", err)
 		return err
@@ -531,11 +584,12 @@ func (os *OracleServer) reportWithCommitment(newRound uint64, lastRoundData *typ
 	os.roundData[newRound] = curRoundData
 
 	// prepare the transaction which carry current round's commitment, and last round's data.
-	curRoundData.Tx, err = os.doReport(curRoundData.CommitmentHash, lastRoundData)
+	curRoundData.Tx, err = os.doReport(ctx, curRoundData.CommitmentHash, lastRoundData)
 	if err != nil {
 		os.logger.Error("do report", "error", err.Error())
 		return err
 	}
+	os.traceTxInclusion(ctx, curRoundData.Tx)
 
 	os.logger.Info("reported last round data and with current round commitment", "TX hash", curRoundData.Tx.Hash(), "Nonce", curRoundData.Tx.Nonce(), "Cost", curRoundData.Tx.Cost())
 
@@ -559,14 +613,15 @@ func (os *OracleServer) reportWithCommitment(newRound uint64, lastRoundData *typ
 }
 
 // report with last round data but without current round commitment, voter is leaving from the committee.
-func (os *OracleServer) reportWithoutCommitment(lastRoundData *types.RoundData) error {
+func (os *OracleServer) reportWithoutCommitment(ctx context.Context, lastRoundData *types.RoundData) error {
 
 	// report with no commitment of current round as voter is leaving from the committee.
-	tx, err := os.doReport(common.Hash{}, lastRoundData)
+	tx, err := os.doReport(ctx, common.Hash{}, lastRoundData)
 	if err != nil {
 		os.logger.Error("do report", "error", err.Error())
 		return err
 	}
+	os.traceTxInclusion(ctx, tx)
 	os.logger.Info("reported last round data and without current round commitment", "TX hash", tx.Hash(), "Nonce", tx.Nonce())
 	return nil
 }
@@ -580,12 +635,22 @@ func (os *OracleServer) AddNewSymbols(newSymbols []string) {
 
 	for _, newS := range newSymbols {
 		if _, ok := symbolsMap[newS]; !ok {
+			symbolsMap[newS] = struct{}{}
 			os.samplingSymbols = append(os.samplingSymbols, newS)
 		}
 	}
 }
 
-func (os *OracleServer) doReport(curRoundCommitmentHash common.Hash, lastRoundData *types.RoundData) (*tp.Transaction, error) {
+func (os *OracleServer) doReport(ctx context.Context, curRoundCommitmentHash common.Hash, lastRoundData *types.RoundData) (tx *tp.Transaction, err error) {
+	_, span := tracing.Start(ctx, "vote", attribute.Bool("commitment", curRoundCommitmentHash != common.Hash{}),
+		attribute.Bool("reveal", lastRoundData != nil && !lastRoundData.MissingData))
+	defer func() {
+		if tx != nil {
+			span.SetAttributes(attribute.String("tx", tx.Hash().String()), attribute.Int64("nonce", int64(tx.Nonce()))) //nolint
+		}
+		tracing.EndWithError(span, err)
+	}()
+
 	chainID, err := os.client.ChainID(context.Background())
 	if err != nil {
 		os.logger.Error("get chain id", "error", err.Error())
@@ -605,52 +670,73 @@ func (os *OracleServer) doReport(curRoundCommitmentHash common.Hash, lastRoundDa
 	// if there is no last round data or there were missing datapoint in last round data, then we just submit the
 	// commitment hash of current round as data might be available at current round. This vote will be reimbursed by the
 	// protocol, however it won't be abused as it is limited by the 1 vote per round rule.
-	if lastRoundData == nil || lastRoundData.MissingData {
-		var reports []contract.IOracleReport
-		return os.oracleContract.Vote(auth, new(big.Int).SetBytes(curRoundCommitmentHash.Bytes()), reports, invalidSalt, config.Version)
+	var reports []contract.IOracleReport
+	salt := invalidSalt
+	if lastRoundData != nil && !lastRoundData.MissingData {
+		// there is last round data, report with current round commitment, and the last round reports and salt to be revealed.
+		reports = lastRoundData.Reports
+		salt = lastRoundData.Salt
+	}
+
+	// simulate the vote before sending it, a reverted vote costs gas and loses the round.
+	commit := new(big.Int).SetBytes(curRoundCommitmentHash.Bytes())
+	reports, salt, err = os.preflightVote(auth.From, auth.GasLimit, commit, reports, salt)
+	if err != nil {
+		return nil, err
 	}
 
-	// there is last round data, report with current round commitment, and the last round reports and salt to be revealed.
-	return os.oracleContract.Vote(auth, new(big.Int).SetBytes(curRoundCommitmentHash.Bytes()), lastRoundData.Reports, lastRoundData.Salt, config.Version)
+	return os.oracleContract.Vote(auth, commit, reports, salt, config.Version)
 }
 
-func (os *OracleServer) buildRoundData(round uint64) (*types.RoundData, error) {
+func (os *OracleServer) buildRoundData(ctx context.Context, round uint64) (*types.RoundData, error) {
 	if len(os.protocolSymbols) == 0 {
 		return nil, types.ErrNoSymbolsObserved
 	}
 
-	prices, err := os.aggregateProtocolSymbolPrices()
+	prices, err := os.aggregateProtocolSymbolPrices(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	// assemble round data with reports, salt and commitment hash.
-	roundData, err := os.assembleReportData(round, os.protocolSymbols, prices)
+	roundData, err := os.assembleReportData(ctx, round, os.protocolSymbols, prices)
 	if err != nil {
 		os.logger.Error("failed to assemble round report data", "error", err.Error())
 		return nil, err
 	}
-	os.logger.Info("assembled round report data", "current round", round, "prices", roundData)
+	roundData.PluginPrices = os.pluginPrices
+	os.logger.Info("assembled round report data", config.LogKeyRound, round, "prices", roundData)
 	return roundData, nil
 }
 
-func (os *OracleServer) aggregateProtocolSymbolPrices() (types.PriceBySymbol, error) {
+func (os *OracleServer) aggregateProtocolSymbolPrices(ctx context.Context) (types.PriceBySymbol, error) {
+	ctx, span := tracing.Start(ctx, "aggregation", attribute.Int64("ts", os.curSampleTS))
+	defer span.End()
+
+	os.backfillSamples(os.curSampleTS)
+
 	prices := make(types.PriceBySymbol)
+	os.pluginPrices = make(map[string]map[string]decimal.Decimal)
+	_, usdcSpan := tracing.Start(ctx, "aggregate symbol", tracing.AttrSymbol.String(USDCUSD))
 	usdcPrice, err := os.aggregatePrice(USDCUSD, os.curSampleTS)
 	if err != nil {
 		os.logger.Error("aggregate USDC-USD price", "error", err.Error())
 	}
+	tracing.EndWithError(usdcSpan, err)
 
 	for _, s := range os.protocolSymbols {
+		_, symbolSpan := tracing.Start(ctx, "aggregate symbol", tracing.AttrSymbol.String(s))
 		// aggregate bridged symbols
 		if s == ATNUSD || s == NTNUSD {
 			if usdcPrice == nil {
+				tracing.EndWithError(symbolSpan, types.ErrNoDataRound)
 				continue
 			}
 
 			p, e := os.aggregateBridgedPrice(s, os.curSampleTS, usdcPrice)
+			tracing.EndWithError(symbolSpan, e)
 			if e != nil {
-				os.logger.Error("aggregate bridged price", "error", e.Error(), "symbol", s)
+				os.logger.Error("aggregate bridged price", "error", e.Error(), config.LogKeySymbol, s)
 				continue
 			}
 			prices[s] = *p
@@ -659,8 +745,9 @@ func (os *OracleServer) aggregateProtocolSymbolPrices() (types.PriceBySymbol, er
 
 		// aggregate none bridged symbols
 		p, e := os.aggregatePrice(s, os.curSampleTS)
+		tracing.EndWithError(symbolSpan, e)
 		if e != nil {
-			os.logger.Debug("no data for aggregation", "reason", e.Error(), "symbol", s)
+			os.logger.Debug("no data for aggregation", "reason", e.Error(), config.LogKeySymbol, s)
 			continue
 		}
 		prices[s] = *p
@@ -691,11 +778,16 @@ func (os *OracleServer) aggregateProtocolSymbolPrices() (types.PriceBySymbol, er
 		}
 	}
 
+	if metrics.Enabled {
+		for _, plugin := range os.runningPlugins {
+			plugin.Reliability().UpdateMetrics()
+		}
+	}
 	return prices, nil
 }
 
 // assemble the final reports, salt and commitment hash.
-func (os *OracleServer) assembleReportData(round uint64, symbols []string, prices types.PriceBySymbol) (*types.RoundData, error) {
+func (os *OracleServer) assembleReportData(ctx context.Context, round uint64, symbols []string, prices types.PriceBySymbol) (*types.RoundData, error) {
 	var roundData = &types.RoundData{
 		RoundID: round,
 		Symbols: symbols,
@@ -709,7 +801,7 @@ func (os *OracleServer) assembleReportData(round uint64, symbols []string, price
 			// This is an edge case, which means there is no liquidity in the market for this symbol.
 			price := pr.Price.Mul(os.pricePrecision).BigInt()
 			if price.Cmp(invalidPrice) == 0 {
-				os.logger.Info("zero price measured from market", "symbol", s)
+				os.logger.Info("zero price measured from market", config.LogKeySymbol, s)
 				missingData = true
 			}
 			reports = append(reports, contract.IOracleReport{
@@ -719,7 +811,7 @@ func (os *OracleServer) assembleReportData(round uint64, symbols []string, price
 		} else {
 			// logging the missing of data points for all symbols
 			missingData = true
-			os.logger.Info("round report miss data point for symbol", "symbol", s)
+			os.logger.Info("round report miss data point for symbol", config.LogKeySymbol, s)
 			reports = append(reports, contract.IOracleReport{
 				Price: invalidPrice,
 			})
@@ -732,7 +824,10 @@ func (os *OracleServer) assembleReportData(round uint64, symbols []string, price
 		return nil, err
 	}
 
+	_, span := tracing.Start(ctx, "commitment hash", tracing.AttrRound.Int64(int64(round)), //nolint
+		attribute.Bool("missing data", missingData))
 	commitmentHash, err := os.commitmentHashComputer.CommitmentHash(reports, salt, os.conf.Key.Address)
+	tracing.EndWithError(span, err)
 	if err != nil {
 		os.logger.Error("failed to compute commitment hash", "error", err.Error())
 		return nil, err
@@ -745,9 +840,130 @@ func (os *OracleServer) assembleReportData(round uint64, symbols []string, price
 	return roundData, nil
 }
 
-func (os *OracleServer) handleNewSymbolsEvent(symbols []string) {
-	// just add symbols to oracle service's symbol pool, thus the oracle service can start to prepare the data.
+// commitmentIncluded checks if the commitment tx of the round data was included on chain with success, the result and
+// the reason of a skipped reveal are recorded in the audit of the round data. It is called once the round of the
+// commitment is over, thus the inclusion window of the commitment has passed, and a missing receipt means that the
+// commitment was not included. If the receipt cannot be queried, the reveal is kept, as the commitment might be there.
+func (os *OracleServer) commitmentIncluded(rd *types.RoundData) bool {
+	var reason string
+	if rd.Tx == nil {
+		reason = "commitment was not sent"
+	} else {
+		receipt, err := os.client.TransactionReceipt(context.Background(), rd.Tx.Hash())
+		switch {
+		case errors.Is(err, ethereum.NotFound):
+			reason = fmt.Sprintf("commitment tx %s is not found", rd.Tx.Hash())
+		case err != nil:
+			rd.Audit.Reason = fmt.Sprintf("cannot get the receipt of commitment tx %s: %s", rd.Tx.Hash(), err.Error())
+			os.logger.Warn("keep the reveal of round data as the commitment receipt is unknown", config.LogKeyRound,
+				rd.RoundID, "error", err.Error())
+			return true
+		case receipt.Status != tp.ReceiptStatusSuccessful:
+			reason = fmt.Sprintf("commitment tx %s failed at height %d", rd.Tx.Hash(), receipt.BlockNumber.Uint64())
+		default:
+			rd.Audit.CommitIncluded = true
+			return true
+		}
+	}
+
+	rd.Audit.RevealSkipped = true
+	rd.Audit.Reason = reason
+	os.logger.Warn("skip the reveal of round data as there is no matching commitment", config.LogKeyRound, rd.RoundID,
+		"reason", reason)
+	if metrics.Enabled {
+		skippedReveals.Inc(1)
+	}
+	return false
+}
+
+// traceTxInclusion traces the inclusion of a vote tx by polling its receipt in the background, it is skipped if there
+// is no span exporter, thus no extra L1 query is made by default. The polling ends once the server is stopped.
+func (os *OracleServer) traceTxInclusion(ctx context.Context, tx *tp.Transaction) {
+	if !tracing.Enabled() || tx == nil {
+		return
+	}
+
+	_, span := tracing.Start(ctx, "tx inclusion", attribute.String("tx", tx.Hash().String()))
+	go func() {
+		ticker := time.NewTicker(oneSecsInterval)
+		defer ticker.Stop()
+		timeout := time.NewTimer(txTraceTimeout)
+		defer timeout.Stop()
+		for {
+			select {
+			case <-os.ctx.Done():
+				tracing.EndWithError(span, os.ctx.Err())
+				return
+			case <-timeout.C:
+				tracing.EndWithError(span, types.ErrTxInclusionTimeout)
+				return
+			case <-ticker.C:
+				receipt, err := os.client.TransactionReceipt(os.ctx, tx.Hash())
+				if err != nil || receipt == nil {
+					continue
+				}
+				span.SetAttributes(attribute.Int64("height", receipt.BlockNumber.Int64()),
+					attribute.Int64("gas used", int64(receipt.GasUsed)), //nolint
+					attribute.Bool("success", receipt.Status == tp.ReceiptStatusSuccessful))
+				span.End()
+				return
+			}
+		}
+	}()
+}
+
+func (os *OracleServer) handleNewSymbolsEvent(symbols []string, activationRound uint64) {
+	// add symbols to oracle service's symbol pool, thus the oracle service can start to prepare the data.
 	os.AddNewSymbols(symbols)
+
+	// the new symbols take effect from the activation round, schedule the transition if it is not reached yet.
+	if activationRound > os.curRound {
+		cpSymbols := make([]string, len(symbols))
+		copy(cpSymbols, symbols)
+		os.symbolTransitions[activationRound] = cpSymbols
+		os.logger.Info("scheduled protocol symbols transition", config.LogKeyRound, activationRound, "symbols", symbols)
+		return
+	}
+
+	os.protocolSymbols = symbols
+}
+
+// updateProtocolSymbols resolves the protocol symbols of the current round. The scheduled transition which reaches its
+// activation round is applied, otherwise the symbols from the oracle contract are taken only if there is no transition
+// pending, thus the reports keep the symbol order of the current round during a transition.
+func (os *OracleServer) updateProtocolSymbols(contractSymbols []string) {
+	var activated uint64
+	for round, symbols := range os.symbolTransitions {
+		if round > os.curRound {
+			continue
+		}
+		if round >= activated {
+			activated = round
+			os.protocolSymbols = symbols
+		}
+		delete(os.symbolTransitions, round)
+	}
+
+	if activated != 0 {
+		os.logger.Info("activated protocol symbols", config.LogKeyRound, os.curRound, "symbols", os.protocolSymbols)
+		return
+	}
+
+	if len(os.symbolTransitions) != 0 {
+		os.logger.Debug("keep protocol symbols until the activation of the upcoming symbols",
+			"symbols", os.protocolSymbols, "contract symbols", contractSymbols)
+		return
+	}
+	os.protocolSymbols = contractSymbols
+}
+
+// upcomingSymbols returns the symbols of the scheduled transitions, they are sampled ahead of the activation.
+func (os *OracleServer) upcomingSymbols() []string {
+	var symbols []string
+	for _, s := range os.symbolTransitions {
+		symbols = append(symbols, s...)
+	}
+	return symbols
 }
 
 // aggregateBridgedPrice ATN-USD or NTN-USD from bridged ATN-USDC or NTN-USDC with USDC-USD price,
@@ -764,7 +980,7 @@ func (os *OracleServer) aggregateBridgedPrice(srcSymbol string, target int64, us
 
 	p, err := os.aggregatePrice(bridgedSymbol, target)
 	if err != nil {
-		os.logger.Error("aggregate bridged price", "error", err.Error(), "symbol", bridgedSymbol)
+		os.logger.Error("aggregate bridged price", "error", err.Error(), config.LogKeySymbol, bridgedSymbol)
 		return nil, err
 	}
 
@@ -776,21 +992,69 @@ func (os *OracleServer) aggregateBridgedPrice(srcSymbol string, target int64, us
 	return p, nil
 }
 
+// backfillSamples asks the plugins which can fetch the historical prices to backfill the samples that missed the target
+// of the round, the plugins are called concurrently and the backfill is bounded by the backfillBudget, as the vote
+// waits for it.
+func (os *OracleServer) backfillSamples(target int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), backfillBudget)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, plugin := range os.runningPlugins {
+		if !plugin.Historical() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(p *pWrapper.PluginWrapper) {
+			defer wg.Done()
+			if err := p.BackfillTarget(ctx, os.samplingSymbols, target); err != nil {
+				os.logger.Warn("cannot backfill samples", "plugin", p.Name(), "target", target, "error", err.Error())
+			}
+		}(plugin)
+	}
+	wg.Wait()
+}
+
+// pluginPrice is the price of a symbol aggregated by a plugin.
+type pluginPrice struct {
+	plugin *pWrapper.PluginWrapper
+	price  types.Price
+}
+
 // aggregatePrice takes the symbol's aggregated data points from all the supported plugins, if there are multiple
-// markets' datapoint, it will do a final VWAP aggregation to form the final reporting value.
+// markets' datapoint, it will do a final VWAP aggregation, or a median aggregation for forex, to form the final
+// reporting value. The volumes and the forex prices are weighted by the reliability of the plugins, and the unreliable
+// plugins are excluded temporarily.
 func (os *OracleServer) aggregatePrice(s string, target int64) (*types.Price, error) {
-	var prices []decimal.Decimal
-	var volumes []*big.Int
+	var direct, derived, excluded []pluginPrice
 	for _, plugin := range os.runningPlugins {
 		p, err := plugin.AggregatedPrice(s, target)
 		if err != nil {
 			continue
 		}
-		prices = append(prices, p.Price)
-		volumes = append(volumes, p.Volume)
+		pp := pluginPrice{plugin: plugin, price: p}
+		if plugin.Reliability().Excluded() {
+			excluded = append(excluded, pp)
+			continue
+		}
+		if meta, ok := plugin.SymbolMetadata(s); ok && meta.Derived {
+			derived = append(derived, pp)
+			continue
+		}
+		direct = append(direct, pp)
 	}
 
-	if len(prices) == 0 {
+	// the prices derived from other symbols are only taken if there is no price quoted directly by the markets.
+	candidates := direct
+	if len(candidates) == 0 {
+		candidates = derived
+	}
+	// the excluded plugins are only taken if there is no price from the others.
+	if len(candidates) == 0 {
+		candidates = excluded
+	}
+
+	if len(candidates) == 0 {
 		historicRoundPrice, err := os.queryHistoricRoundPrice(s)
 		if err != nil {
 			return nil, err
@@ -799,32 +1063,39 @@ func (os *OracleServer) aggregatePrice(s string, target int64) (*types.Price, er
 		return confidenceAdjustedPrice(&historicRoundPrice, target)
 	}
 
+	prices := make([]decimal.Decimal, len(candidates))
+	volumes := make([]*big.Int, len(candidates))
+	weights := make([]decimal.Decimal, len(candidates))
+	for i, c := range candidates {
+		prices[i] = c.price.Price
+		volumes[i] = c.price.Volume
+		weights[i] = reliabilityWeight(c.plugin.Reliability().Score())
+	}
+
 	// compute confidence of the symbol from the num of plugins' samples of it.
 	confidence := ComputeConfidence(s, len(prices), os.conf.ConfidenceStrategy)
 	price := &types.Price{
 		Timestamp:  target,
 		Price:      prices[0],
-		Volume:     volumes[0],
+		Volume:     candidates[0].price.Volume,
 		Symbol:     s,
 		Confidence: confidence,
 	}
 
 	_, isForex := ForexCurrencies[s]
 
-	// we have multiple markets' data for this forex symbol, update the price with median value.
 	if len(prices) > 1 && isForex {
-		p, err := helpers.Median(prices)
+		// we have multiple markets' data for this forex symbol, update the price with the median weighted by the
+		// reliability of the plugins.
+		p, err := helpers.WeightedMedian(prices, weights)
 		if err != nil {
 			return nil, err
 		}
 		price.Price = p
 		price.Volume = types.DefaultVolume
-		return price, nil
-	}
-
-	// we have multiple markets' data for this crypto symbol, update the price with VWAP.
-	if len(prices) > 1 && !isForex {
-		p, vol, err := helpers.VWAP(prices, volumes)
+	} else if len(prices) > 1 {
+		// we have multiple markets' data for this crypto symbol, update the price with VWAP.
+		p, vol, err := helpers.WeightedVWAP(prices, volumes, weights)
 		if err != nil {
 			return nil, err
 		}
@@ -832,9 +1103,56 @@ func (os *OracleServer) aggregatePrice(s string, target int64) (*types.Price, er
 		price.Volume = vol
 	}
 
+	// track the deviation of all the plugins including the excluded ones, thus they can recover from the exclusion.
+	all := append(append(direct, derived...), excluded...)
+	for _, c := range all {
+		c.plugin.Reliability().ObserveDeviation(c.price.Price, price.Price)
+	}
+	os.recordPluginPrices(s, all)
 	return price, nil
 }
 
+// reliabilityWeight is the weight of the volume of a plugin by its reliability score.
+func reliabilityWeight(score float64) decimal.Decimal {
+	if score >= 1 {
+		return decimal.NewFromInt(1)
+	}
+	return decimal.NewFromFloat(score)
+}
+
+// recordPluginPrices keeps the prices of per plugin of the round under aggregation, they are compared with the
+// on-chain median once the round is finalized.
+func (os *OracleServer) recordPluginPrices(symbol string, prices []pluginPrice) {
+	if os.pluginPrices == nil {
+		os.pluginPrices = make(map[string]map[string]decimal.Decimal)
+	}
+	byPlugin := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		byPlugin[p.plugin.Name()] = p.price.Price
+	}
+	os.pluginPrices[symbol] = byPlugin
+}
+
+// observeOnChainMedian tracks the deviation of the plugins' prices from the on-chain median of a round, the prices
+// committed in a round are revealed and aggregated on chain in the next round.
+func (os *OracleServer) observeOnChainMedian(round uint64, symbol string, rd contract.IOracleRoundData) {
+	if !rd.Success || rd.Price == nil || round == 0 {
+		return
+	}
+
+	roundData, ok := os.roundData[round-1]
+	if !ok {
+		return
+	}
+
+	median := decimal.NewFromBigInt(rd.Price, 0).Div(os.pricePrecision)
+	for name, price := range roundData.PluginPrices[symbol] {
+		if plugin, ok := os.runningPlugins[name]; ok {
+			plugin.Reliability().ObserveMedianDeviation(price, median)
+		}
+	}
+}
+
 // queryHistoricRoundPrice queries the last available price for a given symbol from the historic rounds.
 func (os *OracleServer) queryHistoricRoundPrice(symbol string) (types.Price, error) {
 
@@ -866,15 +1184,16 @@ func (os *OracleServer) queryHistoricRoundPrice(symbol string) (types.Price, err
 	return types.Price{}, types.ErrNoDataRound
 }
 
-func (os *OracleServer) samplePrice(symbols []string, ts int64) {
+func (os *OracleServer) samplePrice(traceCtx trace.SpanContext, symbols []string, ts int64) {
 	if os.lastSampledTS == ts {
 		return
 	}
 	cpSymbols := make([]string, len(symbols))
 	copy(cpSymbols, symbols)
 	e := &types.SampleEvent{
-		Symbols: cpSymbols,
-		TS:      ts,
+		Symbols:  cpSymbols,
+		TS:       ts,
+		TraceCtx: traceCtx,
 	}
 	nListener := os.sampleEventFeed.Send(e)
 	os.logger.Debug("sample event is sent to", "num of plugins", nListener)
@@ -886,6 +1205,7 @@ func (os *OracleServer) Start() {
 		case <-os.doneCh:
 			os.regularTicker.Stop()
 			os.psTicker.Stop()
+			os.roundSpan.End()
 			os.logger.Info("oracle service is stopped")
 			return
 
@@ -927,7 +1247,7 @@ func (os *OracleServer) Start() {
 		case penalizeEvent := <-os.chPenalizedEvent:
 
 			os.logger.Warn("Oracle client get penalized as an outlier", "node", penalizeEvent.Participant,
-				"currency symbol", penalizeEvent.Symbol, "median value", penalizeEvent.Median.String(),
+				config.LogKeySymbol, penalizeEvent.Symbol, "median value", penalizeEvent.Median.String(),
 				"reported value", penalizeEvent.Reported.String(), "block", penalizeEvent.Raw.BlockNumber, "slashed amount", penalizeEvent.SlashingAmount.Uint64())
 			os.logger.Warn("your next vote will be postponed", "in blocks", os.conf.VoteBuffer)
 
@@ -961,13 +1281,20 @@ func (os *OracleServer) Start() {
 			os.PluginRuntimeManagement()
 
 		case roundEvent := <-os.chRoundEvent:
-			os.logger.Info("handle new round", "round", roundEvent.Round.Uint64(), "required sampling TS",
+			os.logger.Info("handle new round", config.LogKeyRound, roundEvent.Round.Uint64(), "required sampling TS",
 				roundEvent.Timestamp.Uint64(), "height", roundEvent.Raw.BlockNumber, "round period", roundEvent.VotePeriod.Uint64())
 
 			if metrics.Enabled {
 				oracleRound.Update(roundEvent.Round.Int64())
 			}
 
+			// the round span lasts until the next round event, thus the pre-sampling for the next round and the
+			// inclusion of the vote are traced within the round as well.
+			os.roundSpan.End()
+			os.roundCtx, os.roundSpan = tracing.Start(context.Background(), "round",
+				tracing.AttrRound.Int64(roundEvent.Round.Int64()), attribute.Int64("height", int64(roundEvent.Raw.BlockNumber)), //nolint
+				attribute.Int64("ts", roundEvent.Timestamp.Int64()))
+
 			// save the round rotation info to coordinate the pre-sampling.
 			os.curRound = roundEvent.Round.Uint64()
 			os.votePeriod = roundEvent.VotePeriod.Uint64()
@@ -984,17 +1311,23 @@ func (os *OracleServer) Start() {
 			os.samplingSymbols = os.protocolSymbols
 			// attach the bridger symbols too once the sampling symbols is replaced by protocol symbols.
 			os.AddNewSymbols(bridgerSymbols)
+			// keep the pre-sampling of the upcoming symbols which are not activated yet.
+			os.AddNewSymbols(os.upcomingSymbols())
 		case newSymbolEvent := <-os.chSymbolsEvent:
 			os.logger.Info("handle new symbols", "new symbols", newSymbolEvent.Symbols, "activate at round", newSymbolEvent.Round)
-			os.handleNewSymbolsEvent(newSymbolEvent.Symbols)
+			os.handleNewSymbolsEvent(newSymbolEvent.Symbols, newSymbolEvent.Round.Uint64())
 		case <-os.regularTicker.C:
+			os.supervisePlugins()
+			os.pollPluginMetrics()
+			os.snapshotSamples(time.Now(), false)
 			os.gcRoundData()
-			os.logger.Debug("round rotation", "current oracle round", os.curRound)
+			os.logger.Debug("round rotation", config.LogKeyRound, os.curRound)
 		}
 	}
 }
 
 func (os *OracleServer) Stop() {
+	os.cancel()
 	os.client.Close()
 	os.subRoundEvent.Unsubscribe()
 	os.subSymbolsEvent.Unsubscribe()
@@ -1004,6 +1337,7 @@ func (os *OracleServer) Stop() {
 	}
 
 	os.doneCh <- struct{}{}
+	os.snapshotSamples(time.Now(), true)
 	for _, c := range os.runningPlugins {
 		p := c
 		p.Close()
@@ -1027,7 +1361,16 @@ func (os *OracleServer) PluginRuntimeManagement() {
 
 	// shutdown the plugins which are removed from the plugin directory, or disabled from config.
 	for name, plugin := range os.runningPlugins {
-		if _, ok := binaries[name]; !ok {
+		pConf := plugConfs[name]
+		// shutdown the plugin that is switched between in process and its binary, it is relaunched below.
+		if plugin.InProcess() != runsInProcess(name, &pConf) {
+			os.logger.Info("switching plugin mode", "name", name, "in-process", !plugin.InProcess())
+			plugin.Close()
+			delete(os.runningPlugins, name)
+			continue
+		}
+
+		if _, ok := binaries[name]; !ok && !plugin.InProcess() {
 			os.logger.Info("removing plugin", "name", name)
 			plugin.Close()
 			delete(os.runningPlugins, name)
@@ -1035,7 +1378,6 @@ func (os *OracleServer) PluginRuntimeManagement() {
 		}
 
 		// shutdown the plugin that are runtime disabled.
-		pConf := plugConfs[name]
 		if pConf.Disabled {
 			os.logger.Info("disabling plugin", "name", name)
 			plugin.Close()
@@ -1048,7 +1390,7 @@ func (os *OracleServer) PluginRuntimeManagement() {
 		f := file
 		pConf := plugConfs[f.Name()]
 
-		if pConf.Disabled {
+		if pConf.Disabled || runsInProcess(f.Name(), &pConf) {
 			continue
 		}
 
@@ -1057,8 +1399,14 @@ func (os *OracleServer) PluginRuntimeManagement() {
 			continue
 		}
 
+		// skip to set up plugins until the config rejected by their config schema is corrected.
+		if os.isConfRejected(f.Name(), &pConf, f.ModTime()) {
+			continue
+		}
+
 		os.tryToLaunchPlugin(f, pConf)
 	}
+	os.launchInProcessPlugins(plugConfs)
 
 	if metrics.Enabled {
 		numOfPlugins.Update(int64(len(os.runningPlugins)))
@@ -1068,42 +1416,69 @@ func (os *OracleServer) PluginRuntimeManagement() {
 func (os *OracleServer) tryToLaunchPlugin(f fs.FileInfo, plugConf config.PluginConfig) {
 	plugin, ok := os.runningPlugins[f.Name()]
 	if !ok {
-		os.logger.Info("new plugin discovered, going to setup it: ", f.Name(), f.Mode().String())
-		pluginWrapper, err := os.setupNewPlugin(f.Name(), &plugConf)
-		if err != nil {
+		if os.isRefused(f, &plugConf) {
 			return
 		}
-		os.runningPlugins[f.Name()] = pluginWrapper
+
+		// a failed plugin is restarted by the supervisor, unless its binary is replaced.
+		if s, supervised := os.supervisions[f.Name()]; supervised && s.consecutiveFailures > 0 {
+			if !f.ModTime().After(s.failedAt) {
+				return
+			}
+			os.releasePlugin(f.Name())
+		}
+
+		os.logger.Info("new plugin discovered, going to setup it: ", f.Name(), f.Mode().String())
+		os.launchPlugin(f, &plugConf)
 		return
 	}
 
-	if f.ModTime().After(plugin.StartTime()) || plugin.Exited() {
+	// the exited plugin is restarted by the supervisor with backoff.
+	if f.ModTime().After(plugin.StartTime()) {
 		os.logger.Info("replacing legacy plugin with new one: ", f.Name(), f.Mode().String())
+		os.releasePlugin(f.Name())
 		// stop the legacy plugin
 		plugin.Close()
 		delete(os.runningPlugins, f.Name())
+		os.launchPlugin(f, &plugConf)
+	}
+}
 
-		pluginWrapper, err := os.setupNewPlugin(f.Name(), &plugConf)
-		if err != nil {
-			return
+// launchPlugin sets up the plugin of the binary, the binary is recorded as refused if it fails the integrity check.
+func (os *OracleServer) launchPlugin(f fs.FileInfo, conf *config.PluginConfig) {
+	pluginWrapper, err := os.setupNewPlugin(f.Name(), conf)
+	if err != nil {
+		if isIntegrityError(err) {
+			os.refusePlugin(f, conf, err)
 		}
-		os.runningPlugins[f.Name()] = pluginWrapper
+		return
 	}
+	os.runningPlugins[f.Name()] = pluginWrapper
+	os.restoreSamples(f.Name())
 }
 
 func (os *OracleServer) setupNewPlugin(name string, conf *config.PluginConfig) (*pWrapper.PluginWrapper, error) {
-	if err := os.ApplyPluginConf(name, conf); err != nil {
-		os.logger.Error("apply plugin config", "error", err.Error())
-		return nil, err
+	logger := config.NewLogger(&os.conf.LogConfigs, name, os.conf.LogConfigs.PluginLogLevel(name, os.conf.LoggingLevel))
+	var pluginWrapper *pWrapper.PluginWrapper
+	if runsInProcess(name, conf) {
+		pluginWrapper = pWrapper.NewInProcessPluginWrapper(logger, name, os, conf)
+	} else {
+		secure, err := os.verifyPlugin(name, conf)
+		if err != nil {
+			return nil, err
+		}
+		pluginWrapper = pWrapper.NewPluginWrapper(logger, name, os.conf.PluginDIR, os, conf, secure)
 	}
 
-	pluginWrapper := pWrapper.NewPluginWrapper(os.conf.LoggingLevel, name, os.conf.PluginDIR, os, conf)
 	if err := pluginWrapper.Initialize(os.chainID); err != nil {
 		// if the plugin states that a service key is missing, then we mark it down, thus the runtime discovery can
 		// skip those plugins without a key configured.
 		if errors.Is(err, types.ErrMissingServiceKey) {
 			os.keyRequiredPlugins[name] = struct{}{}
 		}
+		if errors.Is(err, types.ErrInvalidPluginConfig) {
+			os.rejectConf(name, conf)
+		}
 		os.logger.Error("cannot run plugin", "name", name, "error", err.Error())
 		pluginWrapper.CleanPluginProcess()
 		return nil, err
@@ -1116,20 +1491,6 @@ func (os *OracleServer) WatchSampleEvent(sink chan<- *types.SampleEvent) event.S
 	return os.sampleEventFeed.Subscribe(sink)
 }
 
-func (os *OracleServer) ApplyPluginConf(name string, plugConf *config.PluginConfig) error {
-	// set the plugin configuration via system env, thus the plugin can load it on startup.
-	conf, err := json.Marshal(plugConf)
-	if err != nil {
-		os.logger.Error("cannot marshal plugin's configuration", "error", err.Error())
-		return err
-	}
-	if err = o.Setenv(name, string(conf)); err != nil {
-		os.logger.Error("cannot set plugin configuration via system ENV")
-		return err
-	}
-	return nil
-}
-
 // ComputeConfidence calculates the confidence weight based on the number of data samples. Note! Cryptos take
 // fixed strategy as we have very limited number of data sources at the genesis phase. Thus, the confidence
 // computing is just for forex currencies for the time being.
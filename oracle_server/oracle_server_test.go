@@ -5,9 +5,15 @@ import (
 	contract "autonity-oracle/contract_binder/contract"
 	cMock "autonity-oracle/contract_binder/contract/mock"
 	"autonity-oracle/helpers"
+	pWrapper "autonity-oracle/plugin_wrapper"
 	"autonity-oracle/types"
 	"autonity-oracle/types/mock"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	tp "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -21,6 +27,7 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
@@ -160,7 +167,7 @@ func TestOracleServer(t *testing.T) {
 			time.Sleep(time.Second)
 		}
 
-		roundData, err := srv.buildRoundData(1)
+		roundData, err := srv.buildRoundData(context.Background(), 1)
 		require.NoError(t, err)
 		require.Equal(t, uint64(1), roundData.RoundID)
 		require.Equal(t, helpers.DefaultSymbols, roundData.Symbols)
@@ -208,6 +215,9 @@ func TestOracleServer(t *testing.T) {
 		l1Mock.EXPECT().SyncProgress(gomock.Any()).Return(nil, nil)
 		l1Mock.EXPECT().ChainID(gomock.Any()).Return(new(big.Int).SetUint64(1000), nil)
 		l1Mock.EXPECT().BalanceAt(gomock.Any(), gomock.Any(), gomock.Any()).Return(alertBalance, nil)
+		l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, nil)
+		commitTx := tp.NewTx(&tp.DynamicFeeTx{ChainID: new(big.Int).SetUint64(1000), Nonce: 0})
+		l1Mock.EXPECT().TransactionReceipt(gomock.Any(), commitTx.Hash()).Return(&tp.Receipt{Status: tp.ReceiptStatusSuccessful}, nil)
 		srv := NewOracleServer(conf, dialerMock, l1Mock, contractMock)
 
 		// prepare last round data.
@@ -220,8 +230,9 @@ func TestOracleServer(t *testing.T) {
 			}
 		}
 
-		roundData, err := srv.assembleReportData(srv.curRound, helpers.DefaultSymbols, prices)
+		roundData, err := srv.assembleReportData(context.Background(), srv.curRound, helpers.DefaultSymbols, prices)
 		require.NoError(t, err)
+		roundData.Tx = commitTx
 		srv.roundData[srv.curRound] = roundData
 
 		// pre-sampling with data.
@@ -249,6 +260,8 @@ func TestOracleServer(t *testing.T) {
 		hash, err := srv.commitmentHashComputer.CommitmentHash(srv.roundData[srv.curRound].Reports, srv.roundData[srv.curRound].Salt, srv.conf.Key.Address)
 		require.NoError(t, err)
 		require.Equal(t, hash, srv.roundData[srv.curRound].CommitmentHash)
+		require.True(t, srv.roundData[srv.curRound-1].Audit.CommitIncluded)
+		require.False(t, srv.roundData[srv.curRound-1].Audit.RevealSkipped)
 
 		srv.runningPlugins["template_plugin"].Close()
 	})
@@ -276,9 +289,27 @@ func TestOracleServer(t *testing.T) {
 		require.Equal(t, votePeriod.Uint64(), srv.votePeriod)
 		require.Equal(t, 1, len(srv.runningPlugins))
 
-		nSymbols := append(helpers.DefaultSymbols, "NTNETH", "NTNBTC", "NTNCNY")
-		srv.handleNewSymbolsEvent(nSymbols)
+		nSymbols := append([]string{"NTNETH", "NTNBTC", "NTNCNY"}, helpers.DefaultSymbols...)
+		activationRound := currentRound.Uint64() + 2
+		srv.handleNewSymbolsEvent(nSymbols, activationRound)
 		require.Equal(t, len(nSymbols)+len(bridgerSymbols), len(srv.samplingSymbols))
+		require.Equal(t, nSymbols, srv.symbolTransitions[activationRound])
+
+		// the reports keep the old symbol order until the activation round, even if the contract returns the new ones.
+		srv.curRound = activationRound - 1
+		srv.updateProtocolSymbols(nSymbols)
+		require.Equal(t, helpers.DefaultSymbols, srv.protocolSymbols)
+		require.ElementsMatch(t, nSymbols, srv.upcomingSymbols())
+
+		// the transition takes effect at the activation round.
+		srv.curRound = activationRound
+		srv.updateProtocolSymbols(helpers.DefaultSymbols)
+		require.Equal(t, nSymbols, srv.protocolSymbols)
+		require.Equal(t, 0, len(srv.symbolTransitions))
+
+		// the contract symbols are taken once there is no transition pending.
+		srv.updateProtocolSymbols(helpers.DefaultSymbols)
+		require.Equal(t, helpers.DefaultSymbols, srv.protocolSymbols)
 		srv.runningPlugins["template_plugin"].Close()
 	})
 
@@ -419,6 +450,133 @@ func TestOracleServer(t *testing.T) {
 		require.Equal(t, 0, len(srv.runningPlugins))
 	})
 
+	t.Run("test plugin supervisor, restart and quarantine crashed plugin", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dialerMock := mock.NewMockDialer(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		contractMock.EXPECT().GetRound(nil).Return(currentRound, nil)
+		contractMock.EXPECT().GetSymbols(nil).Return(helpers.DefaultSymbols, nil)
+		contractMock.EXPECT().GetVotePeriod(nil).Return(votePeriod, nil)
+		contractMock.EXPECT().WatchNewRound(gomock.Any(), gomock.Any()).Return(subRoundEvent, nil)
+		contractMock.EXPECT().WatchNewSymbols(gomock.Any(), gomock.Any()).Return(subSymbolsEvent, nil)
+		contractMock.EXPECT().WatchPenalized(gomock.Any(), gomock.Any(), gomock.Any()).Return(subPenalizeEvent, nil)
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		l1Mock.EXPECT().ChainID(gomock.Any()).Return(ChainIDPiccadilly, nil)
+		srv := NewOracleServer(conf, dialerMock, l1Mock, contractMock)
+		require.Equal(t, 1, len(srv.runningPlugins))
+
+		defaultBackoff, defaultThreshold := restartBackoffBase, quarantineThreshold
+		restartBackoffBase, quarantineThreshold = 10*time.Millisecond, 2
+		defer func() { restartBackoffBase, quarantineThreshold = defaultBackoff, defaultThreshold }()
+
+		crash := func() {
+			srv.runningPlugins["template_plugin"].CleanPluginProcess()
+			require.Eventually(t, srv.runningPlugins["template_plugin"].Exited, time.Second, 10*time.Millisecond)
+		}
+
+		// the exited plugin is restarted by the supervisor after the backoff.
+		crash()
+		srv.supervisePlugins()
+		require.Equal(t, 0, len(srv.runningPlugins))
+		// an fs event does not bypass the backoff.
+		srv.PluginRuntimeManagement()
+		require.Equal(t, 0, len(srv.runningPlugins))
+		time.Sleep(2 * restartBackoffBase)
+		srv.supervisePlugins()
+		require.Equal(t, 1, len(srv.runningPlugins))
+		require.Equal(t, int64(1), srv.PluginRestarts("template_plugin"))
+
+		// the crash looping plugin is quarantined.
+		crash()
+		srv.supervisePlugins()
+		require.True(t, srv.supervisions["template_plugin"].quarantined)
+		time.Sleep(2 * restartBackoffBase)
+		srv.supervisePlugins()
+		require.Equal(t, 0, len(srv.runningPlugins))
+
+		// the quarantine is lifted once the plugin binary is replaced.
+		now := time.Now().Add(time.Second)
+		require.NoError(t, os.Chtimes(filepath.Join(srv.conf.PluginDIR, "template_plugin"), now, now))
+		srv.PluginRuntimeManagement()
+		require.Equal(t, 1, len(srv.runningPlugins))
+		require.False(t, srv.supervisions["template_plugin"].quarantined)
+		require.Equal(t, int64(1), srv.PluginRestarts("template_plugin"))
+		srv.runningPlugins["template_plugin"].Close()
+	})
+
+	t.Run("test plugin supervisor, restart plugin on resource limit breach", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("the resource limits are applied on Linux only")
+		}
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dialerMock := mock.NewMockDialer(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		contractMock.EXPECT().GetRound(nil).Return(currentRound, nil)
+		contractMock.EXPECT().GetSymbols(nil).Return(helpers.DefaultSymbols, nil)
+		contractMock.EXPECT().GetVotePeriod(nil).Return(votePeriod, nil)
+		contractMock.EXPECT().WatchNewRound(gomock.Any(), gomock.Any()).Return(subRoundEvent, nil)
+		contractMock.EXPECT().WatchNewSymbols(gomock.Any(), gomock.Any()).Return(subSymbolsEvent, nil)
+		contractMock.EXPECT().WatchPenalized(gomock.Any(), gomock.Any(), gomock.Any()).Return(subPenalizeEvent, nil)
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		l1Mock.EXPECT().ChainID(gomock.Any()).Return(ChainIDPiccadilly, nil)
+
+		limited := *conf
+		limited.PluginConfigs = map[string]config.PluginConfig{
+			"template_plugin": {Name: "template_plugin", Limits: config.ResourceLimits{MaxRSS: 1}},
+		}
+		srv := NewOracleServer(&limited, dialerMock, l1Mock, contractMock)
+		require.Equal(t, 1, len(srv.runningPlugins))
+
+		// the plugin breaching its memory limit is killed and scheduled for a restart.
+		srv.supervisePlugins()
+		require.Equal(t, 0, len(srv.runningPlugins))
+		require.Equal(t, int64(1), srv.PluginLimitBreaches("template_plugin"))
+		require.Equal(t, 1, srv.supervisions["template_plugin"].consecutiveFailures)
+	})
+
+	t.Run("test plugin integrity, refuse unknown and modified plugin binaries", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		dialerMock := mock.NewMockDialer(ctrl)
+		contractMock := cMock.NewMockContractAPI(ctrl)
+		contractMock.EXPECT().GetRound(nil).Return(currentRound, nil)
+		contractMock.EXPECT().GetSymbols(nil).Return(helpers.DefaultSymbols, nil)
+		contractMock.EXPECT().GetVotePeriod(nil).Return(votePeriod, nil)
+		contractMock.EXPECT().WatchNewRound(gomock.Any(), gomock.Any()).Return(subRoundEvent, nil)
+		contractMock.EXPECT().WatchNewSymbols(gomock.Any(), gomock.Any()).Return(subSymbolsEvent, nil)
+		contractMock.EXPECT().WatchPenalized(gomock.Any(), gomock.Any(), gomock.Any()).Return(subPenalizeEvent, nil)
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		l1Mock.EXPECT().ChainID(gomock.Any()).Return(ChainIDPiccadilly, nil)
+
+		enforced := *conf
+		enforced.IntegrityConfigs = config.IntegrityConfig{Enforce: true}
+		srv := NewOracleServer(&enforced, dialerMock, l1Mock, contractMock)
+		require.Equal(t, 0, len(srv.runningPlugins))
+		require.Contains(t, srv.refusedBinaries, "template_plugin")
+
+		binary, err := os.ReadFile(filepath.Join(srv.conf.PluginDIR, "template_plugin"))
+		require.NoError(t, err)
+		sum := sha256.Sum256(binary)
+		f, err := os.Stat(filepath.Join(srv.conf.PluginDIR, "template_plugin"))
+		require.NoError(t, err)
+
+		// the modified binary is refused.
+		wrong := sha256.Sum256([]byte("modified binary"))
+		srv.tryToLaunchPlugin(f, config.PluginConfig{Name: "template_plugin", Checksum: hex.EncodeToString(wrong[:])})
+		require.Equal(t, 0, len(srv.runningPlugins))
+
+		// the binary is launched once its checksum is pinned.
+		srv.tryToLaunchPlugin(f, config.PluginConfig{Name: "template_plugin", Checksum: hex.EncodeToString(sum[:])})
+		require.Equal(t, 1, len(srv.runningPlugins))
+		require.NotContains(t, srv.refusedBinaries, "template_plugin")
+		srv.runningPlugins["template_plugin"].Close()
+	})
+
 	t.Run("gcRounddata", func(t *testing.T) {
 		os := &OracleServer{
 			roundData: make(map[uint64]*types.RoundData),
@@ -563,3 +721,74 @@ func TestComputeConfidence(t *testing.T) {
 		})
 	}
 }
+
+// TestCommitmentIncluded tests the decision of revealing the last round data on the receipt of its commitment.
+func TestCommitmentIncluded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	l1Mock := mock.NewMockBlockchain(ctrl)
+	srv := &OracleServer{logger: hclog.NewNullLogger(), client: l1Mock}
+
+	// the commitment was never sent.
+	rd := &types.RoundData{RoundID: 1}
+	require.False(t, srv.commitmentIncluded(rd))
+	require.True(t, rd.Audit.RevealSkipped)
+
+	// the commitment tx is not found on chain.
+	tx := tp.NewTx(&tp.DynamicFeeTx{ChainID: new(big.Int).SetUint64(1000), Nonce: 1})
+	l1Mock.EXPECT().TransactionReceipt(gomock.Any(), tx.Hash()).Return(nil, ethereum.NotFound)
+	rd = &types.RoundData{RoundID: 2, Tx: tx}
+	require.False(t, srv.commitmentIncluded(rd))
+	require.True(t, rd.Audit.RevealSkipped)
+	require.Contains(t, rd.Audit.Reason, "not found")
+
+	// the commitment tx was reverted.
+	l1Mock.EXPECT().TransactionReceipt(gomock.Any(), tx.Hash()).Return(&tp.Receipt{Status: tp.ReceiptStatusFailed,
+		BlockNumber: big.NewInt(100)}, nil)
+	rd = &types.RoundData{RoundID: 3, Tx: tx}
+	require.False(t, srv.commitmentIncluded(rd))
+	require.True(t, rd.Audit.RevealSkipped)
+
+	// the receipt cannot be queried, the reveal is kept.
+	l1Mock.EXPECT().TransactionReceipt(gomock.Any(), tx.Hash()).Return(nil, errors.New("connection refused"))
+	rd = &types.RoundData{RoundID: 4, Tx: tx}
+	require.True(t, srv.commitmentIncluded(rd))
+	require.False(t, rd.Audit.RevealSkipped)
+	require.False(t, rd.Audit.CommitIncluded)
+
+	// the commitment tx was included.
+	l1Mock.EXPECT().TransactionReceipt(gomock.Any(), tx.Hash()).Return(&tp.Receipt{Status: tp.ReceiptStatusSuccessful}, nil)
+	rd = &types.RoundData{RoundID: 5, Tx: tx}
+	require.True(t, srv.commitmentIncluded(rd))
+	require.True(t, rd.Audit.CommitIncluded)
+	require.False(t, rd.Audit.RevealSkipped)
+}
+
+// TestWeightedForexAggregation checks that the forex prices are weighted by the reliability of the plugins.
+func TestWeightedForexAggregation(t *testing.T) {
+	target := time.Now().Unix()
+	newPlugin := func(name string, price string) *pWrapper.PluginWrapper {
+		p := pWrapper.NewInProcessPluginWrapper(hclog.NewNullLogger(), name, nil, &config.PluginConfig{Name: name})
+		p.AddSample([]types.Price{{Symbol: "EUR-USD", Price: decimal.RequireFromString(price), Timestamp: target,
+			Volume: types.DefaultVolume}}, target)
+		return p
+	}
+	reliable, unreliable := newPlugin("forex_a", "1.0"), newPlugin("forex_b", "1.2")
+	srv := &OracleServer{conf: &config.Config{}, logger: hclog.NewNullLogger(),
+		runningPlugins: map[string]*pWrapper.PluginWrapper{"forex_a": reliable, "forex_b": unreliable}}
+
+	// the plugins of the same reliability are aggregated by the median.
+	price, err := srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1.1", price.Price.String())
+
+	// the down-weighted plugin moves the price less, while it is not excluded.
+	for i := 0; i < 10; i++ {
+		unreliable.Reliability().ObserveFetch(errors.New("fetch failure"), 0)
+	}
+	require.Less(t, unreliable.Reliability().Score(), 1.0)
+	require.False(t, unreliable.Reliability().Excluded())
+	price, err = srv.aggregatePrice("EUR-USD", target)
+	require.NoError(t, err)
+	require.Equal(t, "1", price.Price.String())
+}
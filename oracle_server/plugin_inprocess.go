@@ -0,0 +1,68 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	common2 "autonity-oracle/plugins/common"
+	"sort"
+	"time"
+)
+
+// runsInProcess returns true if the plugin is selected to run in process by its config and it is linked into the
+// oracle server, otherwise the plugin binary is launched.
+func runsInProcess(name string, conf *config.PluginConfig) bool {
+	return conf.InProcess && common2.IsRegistered(name)
+}
+
+func hasInProcessPlugins(confs map[string]config.PluginConfig) bool {
+	for name, conf := range confs {
+		c := conf
+		if !c.Disabled && runsInProcess(name, &c) {
+			return true
+		}
+	}
+	return false
+}
+
+// launchInProcessPlugins sets up the plugins which are selected to run in process and which are not running yet, they
+// run side by side with the plugin binaries behind the same PluginWrapper.
+func (os *OracleServer) launchInProcessPlugins(confs map[string]config.PluginConfig) {
+	names := make([]string, 0, len(confs))
+	for name := range confs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		conf := confs[name]
+		if conf.Disabled || !conf.InProcess {
+			continue
+		}
+
+		if !common2.IsRegistered(name) {
+			os.logger.Warn("plugin is not linked into the oracle server, its binary is launched instead", "name", name,
+				"linked plugins", common2.RegisteredAdapters())
+			continue
+		}
+
+		if _, ok := os.runningPlugins[name]; ok {
+			continue
+		}
+
+		// skip to set up plugins until there is a service key is presented at plugin-confs.yml
+		if _, ok := os.keyRequiredPlugins[name]; ok && conf.Key == "" {
+			continue
+		}
+
+		if os.isConfRejected(name, &conf, time.Time{}) {
+			continue
+		}
+
+		os.logger.Info("setting up in-process plugin", "name", name)
+		pluginWrapper, err := os.setupNewPlugin(name, &conf)
+		if err != nil {
+			continue
+		}
+		os.runningPlugins[name] = pluginWrapper
+		os.restoreSamples(name)
+	}
+}
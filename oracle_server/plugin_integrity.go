@@ -0,0 +1,68 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	"autonity-oracle/types"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/hashicorp/go-plugin"
+	"io/fs"
+	"time"
+)
+
+var integrityFailures = metrics.GetOrRegisterCounter("oracle/plugins/integrity_failures", nil)
+
+// refusedBinary is a plugin binary that failed the integrity verification, it is not verified again until the binary
+// is replaced or its pinned checksum is changed.
+type refusedBinary struct {
+	modTime  time.Time
+	checksum string
+}
+
+// verifyPlugin verifies the plugin binary with the integrity configs before it is launched, please refer to
+// pWrapper.VerifyBinary.
+func (os *OracleServer) verifyPlugin(name string, conf *config.PluginConfig) (*plugin.SecureConfig, error) {
+	operatorKey, err := os.conf.IntegrityConfigs.OperatorKey()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", types.ErrPluginIntegrity, err.Error())
+	}
+
+	secure, err := pWrapper.VerifyBinary(os.conf.PluginDIR, name, conf.Checksum, operatorKey, os.conf.IntegrityConfigs.Enforce)
+	if err != nil {
+		return nil, err
+	}
+	if secure == nil {
+		os.logger.Warn("launching plugin without integrity verification, pin its checksum or sign it", "name", name)
+	}
+	return secure, nil
+}
+
+// refusePlugin records the binary which failed the integrity verification and raises the alert.
+func (os *OracleServer) refusePlugin(f fs.FileInfo, conf *config.PluginConfig, err error) {
+	os.refusedBinaries[f.Name()] = refusedBinary{modTime: f.ModTime(), checksum: conf.Checksum}
+	os.logger.Error("ALERT: refused to launch untrusted plugin binary", "name", f.Name(), "error", err.Error())
+	if metrics.Enabled {
+		integrityFailures.Inc(1)
+	}
+}
+
+// isRefused returns true if the binary was refused and neither the binary nor its pinned checksum is changed since then.
+func (os *OracleServer) isRefused(f fs.FileInfo, conf *config.PluginConfig) bool {
+	refused, ok := os.refusedBinaries[f.Name()]
+	if !ok {
+		return false
+	}
+	if refused.modTime.Equal(f.ModTime()) && refused.checksum == conf.Checksum {
+		return true
+	}
+	delete(os.refusedBinaries, f.Name())
+	return false
+}
+
+// isIntegrityError returns true if the error is raised by the verification before the launch, or by the checksum check
+// of go-plugin on the launch.
+func isIntegrityError(err error) bool {
+	return errors.Is(err, types.ErrPluginIntegrity) || errors.Is(err, plugin.ErrChecksumsDoNotMatch)
+}
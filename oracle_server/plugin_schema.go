@@ -0,0 +1,34 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	"reflect"
+	"time"
+)
+
+// invalidConf is the plugin config that does not match the config schema published by the plugin.
+type invalidConf struct {
+	conf config.PluginConfig
+	at   time.Time
+}
+
+// rejectConf records the config of a plugin rejected by its config schema, thus the plugin is not relaunched on each
+// discovery with the same config.
+func (os *OracleServer) rejectConf(name string, conf *config.PluginConfig) {
+	os.invalidConfs[name] = invalidConf{conf: *conf, at: time.Now()}
+}
+
+// isConfRejected returns true if the config of the plugin was rejected by its config schema, and neither the config
+// nor the binary of the plugin is changed since then, the in-process plugins take a zero modTime.
+func (os *OracleServer) isConfRejected(name string, conf *config.PluginConfig, modTime time.Time) bool {
+	rejected, ok := os.invalidConfs[name]
+	if !ok {
+		return false
+	}
+
+	if !reflect.DeepEqual(rejected.conf, *conf) || modTime.After(rejected.at) {
+		delete(os.invalidConfs, name)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,214 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/helpers"
+	"github.com/ethereum/go-ethereum/metrics"
+	"strings"
+	"time"
+)
+
+var (
+	restartBackoffBase  = tenSecsInterval  // the backoff before the first restart of an exited plugin.
+	restartBackoffMax   = 10 * time.Minute // the backoff is doubled on each consecutive failure up to this limit.
+	stableRunPeriod     = 10 * time.Minute // a plugin which runs for this period is not counted as crash looping anymore.
+	quarantineThreshold = 5                // the consecutive failures to quarantine a crash looping plugin.
+
+	quarantinedPlugins = metrics.GetOrRegisterGauge("oracle/plugins/quarantined", nil)
+)
+
+// pluginSupervision tracks the failures and the restarts of a plugin whose process exited unexpectedly.
+type pluginSupervision struct {
+	restarts            int64     // the total restarts of the plugin.
+	breaches            int64     // the total resource limit breaches of the plugin.
+	consecutiveFailures int       // the failures since the plugin ran stably.
+	failedAt            time.Time // the time of the last failure.
+	nextRestartAt       time.Time
+	quarantined         bool
+
+	restartCounter    metrics.Counter
+	breachCounter     metrics.Counter
+	quarantinedMetric metrics.Gauge
+}
+
+func newPluginSupervision(name string) *pluginSupervision {
+	return &pluginSupervision{
+		restartCounter:    metrics.GetOrRegisterCounter(strings.Join([]string{"oracle", name, "restarts"}, "/"), nil),
+		breachCounter:     metrics.GetOrRegisterCounter(strings.Join([]string{"oracle", name, "limit_breaches"}, "/"), nil),
+		quarantinedMetric: metrics.GetOrRegisterGauge(strings.Join([]string{"oracle", name, "quarantined"}, "/"), nil),
+	}
+}
+
+// backoff returns the delay of the next restart, it doubles on each consecutive failure.
+func (s *pluginSupervision) backoff() time.Duration {
+	backoff := restartBackoffBase
+	for i := 1; i < s.consecutiveFailures && backoff < restartBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > restartBackoffMax {
+		backoff = restartBackoffMax
+	}
+	return backoff
+}
+
+// pollPluginMetrics polls the plugin-side metrics of the running plugins, it runs in the regular ticker of the main loop.
+func (os *OracleServer) pollPluginMetrics() {
+	for _, p := range os.runningPlugins {
+		p.PollMetrics()
+	}
+}
+
+// supervisePlugins detects the exited plugin processes and the ones breaching their resource limits, and restarts them
+// with exponential backoff, it runs in the regular ticker of the main loop. A plugin that fails quarantineThreshold
+// times in a row is quarantined until its binary is replaced.
+func (os *OracleServer) supervisePlugins() {
+	now := time.Now()
+	for name, plugin := range os.runningPlugins {
+		breach := plugin.CheckLimits()
+		if breach == "" && !plugin.Exited() {
+			if s, ok := os.supervisions[name]; ok && s.consecutiveFailures > 0 && now.Sub(plugin.StartTime()) > stableRunPeriod {
+				os.logger.Info("plugin recovered", "name", name, "restarts", s.restarts)
+				s.consecutiveFailures = 0
+			}
+			continue
+		}
+
+		if breach != "" {
+			os.logger.Warn("plugin process breached its resource limit", "name", name, "limit", breach)
+		} else {
+			os.logger.Warn("plugin process exited", "name", name)
+		}
+		plugin.Close()
+		delete(os.runningPlugins, name)
+		os.recordPluginFailure(name, now)
+		if breach != "" {
+			s := os.supervisions[name]
+			s.breaches++
+			if metrics.Enabled {
+				s.breachCounter.Inc(1)
+			}
+		}
+	}
+
+	for name, s := range os.supervisions {
+		if _, ok := os.runningPlugins[name]; ok || s.quarantined || s.consecutiveFailures == 0 || now.Before(s.nextRestartAt) {
+			continue
+		}
+		os.restartPlugin(name, s, now)
+	}
+
+	if metrics.Enabled {
+		numOfPlugins.Update(int64(len(os.runningPlugins)))
+	}
+}
+
+func (os *OracleServer) restartPlugin(name string, s *pluginSupervision, now time.Time) {
+	plugConfs, err := config.LoadPluginsConfig(os.conf.ConfigFile)
+	if err != nil {
+		os.logger.Error("cannot load plugin configuration", "error", err.Error())
+		return
+	}
+
+	binaries, err := helpers.ListPlugins(os.conf.PluginDIR)
+	if err != nil {
+		os.logger.Error("list plugin", "error", err.Error())
+		return
+	}
+
+	// stop supervising the plugin that is removed or disabled, or that is switched to run in process.
+	pConf := plugConfs[name]
+	if _, ok := binaries[name]; !ok || pConf.Disabled || runsInProcess(name, &pConf) {
+		delete(os.supervisions, name)
+		return
+	}
+
+	s.restarts++
+	if metrics.Enabled {
+		s.restartCounter.Inc(1)
+	}
+	os.logger.Info("restarting plugin", "name", name, "restarts", s.restarts, "consecutive failures", s.consecutiveFailures)
+
+	pluginWrapper, err := os.setupNewPlugin(name, &pConf)
+	if err != nil {
+		// the untrusted binary is handed over to the runtime discovery, which refuses it until it is replaced.
+		if isIntegrityError(err) {
+			delete(os.supervisions, name)
+			return
+		}
+		os.recordPluginFailure(name, now)
+		return
+	}
+	os.runningPlugins[name] = pluginWrapper
+	os.restoreSamples(name)
+}
+
+// recordPluginFailure counts a failure of the plugin, it schedules the next restart or quarantines the plugin.
+func (os *OracleServer) recordPluginFailure(name string, now time.Time) {
+	s, ok := os.supervisions[name]
+	if !ok {
+		s = newPluginSupervision(name)
+		os.supervisions[name] = s
+	}
+
+	s.consecutiveFailures++
+	s.failedAt = now
+	if s.consecutiveFailures >= quarantineThreshold {
+		s.quarantined = true
+		os.logger.Error("plugin is crash looping, it is quarantined until its binary is replaced", "name", name,
+			"restarts", s.restarts, "consecutive failures", s.consecutiveFailures)
+		if metrics.Enabled {
+			s.quarantinedMetric.Update(1)
+			quarantinedPlugins.Update(int64(os.numOfQuarantinedPlugins()))
+		}
+		return
+	}
+
+	s.nextRestartAt = now.Add(s.backoff())
+	os.logger.Warn("plugin failure, it will be restarted", "name", name, "at", s.nextRestartAt,
+		"consecutive failures", s.consecutiveFailures)
+}
+
+// releasePlugin resets the failures of the plugin once its binary is replaced, thus the backoff and the quarantine are
+// lifted, while the restarts are kept.
+func (os *OracleServer) releasePlugin(name string) {
+	s, ok := os.supervisions[name]
+	if !ok {
+		return
+	}
+
+	s.consecutiveFailures = 0
+	if s.quarantined {
+		s.quarantined = false
+		os.logger.Info("plugin binary is replaced, lift the quarantine", "name", name)
+		if metrics.Enabled {
+			s.quarantinedMetric.Update(0)
+			quarantinedPlugins.Update(int64(os.numOfQuarantinedPlugins()))
+		}
+	}
+}
+
+func (os *OracleServer) numOfQuarantinedPlugins() int {
+	n := 0
+	for _, s := range os.supervisions {
+		if s.quarantined {
+			n++
+		}
+	}
+	return n
+}
+
+// PluginLimitBreaches returns the number of resource limit breaches of a plugin.
+func (os *OracleServer) PluginLimitBreaches(name string) int64 {
+	if s, ok := os.supervisions[name]; ok {
+		return s.breaches
+	}
+	return 0
+}
+
+// PluginRestarts returns the number of restarts of a plugin by the supervisor.
+func (os *OracleServer) PluginRestarts(name string) int64 {
+	if s, ok := os.supervisions[name]; ok {
+		return s.restarts
+	}
+	return 0
+}
@@ -0,0 +1,19 @@
+package oracleserver
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestPluginSupervisionBackoff(t *testing.T) {
+	s := &pluginSupervision{}
+	expected := []time.Duration{restartBackoffBase, 2 * restartBackoffBase, 4 * restartBackoffBase, 8 * restartBackoffBase}
+	for _, backoff := range expected {
+		s.consecutiveFailures++
+		require.Equal(t, backoff, s.backoff())
+	}
+
+	s.consecutiveFailures = 100
+	require.Equal(t, restartBackoffMax, s.backoff())
+}
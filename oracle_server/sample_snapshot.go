@@ -0,0 +1,45 @@
+package oracleserver
+
+import (
+	"time"
+)
+
+// restoreSamples reloads the recent samples of a newly launched plugin from its snapshot if the snapshots are enabled.
+func (os *OracleServer) restoreSamples(name string) {
+	if !os.conf.SnapshotConfigs.Enable {
+		return
+	}
+
+	plugin, ok := os.runningPlugins[name]
+	if !ok {
+		return
+	}
+
+	loaded, err := plugin.LoadSamples(os.conf.SnapshotConfigs.Dir)
+	if err != nil {
+		os.logger.Warn("cannot reload samples snapshot", "name", name, "error", err.Error())
+		return
+	}
+	if loaded > 0 {
+		os.logger.Info("reloaded samples snapshot", "name", name, "samples", loaded)
+	}
+}
+
+// snapshotSamples writes the snapshots of the running plugins' samples, it runs in the regular ticker of the main loop
+// and it is throttled by the interval of the snapshot configs, unless it is forced on the shutdown.
+func (os *OracleServer) snapshotSamples(now time.Time, force bool) {
+	conf := &os.conf.SnapshotConfigs
+	if !conf.Enable {
+		return
+	}
+	if !force && now.Sub(os.lastSnapshotAt) < time.Duration(conf.Interval)*time.Second {
+		return
+	}
+
+	os.lastSnapshotAt = now
+	for name, plugin := range os.runningPlugins {
+		if err := plugin.SaveSamples(conf.Dir); err != nil {
+			os.logger.Warn("cannot write samples snapshot", "name", name, "error", err.Error())
+		}
+	}
+}
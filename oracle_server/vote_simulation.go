@@ -0,0 +1,165 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	contract "autonity-oracle/contract_binder/contract"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+	"strings"
+)
+
+// voteErrorClass classifies the failure of a vote simulation, the oracle server reacts on a vote failure by its class.
+type voteErrorClass int
+
+const (
+	voteErrTransient     voteErrorClass = iota // the simulation could not be done, the vote is sent anyway.
+	voteErrAlreadyVoted                        // the voter had voted in this round already.
+	voteErrNotVoter                            // the sender is not a voter of the committee.
+	voteErrInvalidReveal                       // the revealed reports or salt cannot be accepted by the contract.
+)
+
+var (
+	// voteRevertReasons maps the exact revert reasons of the oracle contract to the error classes, the other reverts are
+	// taken as transient, as the pending state that the vote is simulated on can differ from the one it is mined on.
+	voteRevertReasons = map[string]voteErrorClass{
+		"already voted":             voteErrAlreadyVoted,
+		"restricted to only voters": voteErrNotVoter,
+		"commit mismatch":           voteErrInvalidReveal,
+	}
+
+	voteSimulationFailures = map[voteErrorClass]metrics.Counter{
+		voteErrTransient:     metrics.GetOrRegisterCounter("oracle/vote/simulation/transient", nil),
+		voteErrAlreadyVoted:  metrics.GetOrRegisterCounter("oracle/vote/simulation/voted", nil),
+		voteErrNotVoter:      metrics.GetOrRegisterCounter("oracle/vote/simulation/notvoter", nil),
+		voteErrInvalidReveal: metrics.GetOrRegisterCounter("oracle/vote/simulation/reveal", nil),
+	}
+
+	errVoteReverted = errors.New("vote reverted in simulation")
+)
+
+func (c voteErrorClass) String() string {
+	switch c {
+	case voteErrTransient:
+		return "transient"
+	case voteErrAlreadyVoted:
+		return "already voted"
+	case voteErrNotVoter:
+		return "not voter"
+	case voteErrInvalidReveal:
+		return "invalid reveal"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(c))
+	}
+}
+
+// preflightVote simulates the vote against the pending state before it is sent. It resolves the reports and salt to
+// be sent: the reveal is dropped if the contract cannot accept it, thus the commitment of the current round is still
+// delivered. It returns an error if the vote would be reverted anyway, to save the gas of a reverted tx.
+func (os *OracleServer) preflightVote(from common.Address, gas uint64, commit *big.Int, reports []contract.IOracleReport,
+	salt *big.Int) ([]contract.IOracleReport, *big.Int, error) {
+	err := os.simulateVote(from, gas, commit, reports, salt)
+	if err == nil {
+		return reports, salt, nil
+	}
+
+	class, reason := os.classifyVoteError(err)
+	if metrics.Enabled {
+		voteSimulationFailures[class].Inc(1)
+	}
+
+	switch class {
+	case voteErrTransient:
+		os.logger.Warn("cannot simulate vote, send it without simulation", "error", err.Error())
+		return reports, salt, nil
+	case voteErrInvalidReveal:
+		if len(reports) == 0 {
+			break
+		}
+		os.logger.Warn("vote simulation reverted on the reveal, retry without reveal", "reason", reason)
+		var noReports []contract.IOracleReport
+		if err = os.simulateVote(from, gas, commit, noReports, invalidSalt); err == nil {
+			return noReports, invalidSalt, nil
+		}
+		if class, reason = os.classifyVoteError(err); class == voteErrTransient {
+			os.logger.Warn("cannot simulate vote without reveal, send it without reveal", "reason", reason)
+			return noReports, invalidSalt, nil
+		}
+	}
+
+	os.logger.Error("abort vote since it is reverted in simulation", "class", class.String(), "reason", reason)
+	return nil, nil, fmt.Errorf("%w: %s, reason: %s", errVoteReverted, class.String(), reason)
+}
+
+// simulateVote calls the vote of the oracle contract on the pending state.
+func (os *OracleServer) simulateVote(from common.Address, gas uint64, commit *big.Int, reports []contract.IOracleReport,
+	salt *big.Int) error {
+	data, err := os.oracleABI.Pack("vote", commit, reports, salt, config.Version)
+	if err != nil {
+		return err
+	}
+
+	msg := ethereum.CallMsg{
+		From: from,
+		To:   &os.conf.OracleContract,
+		Gas:  gas,
+		Data: data,
+	}
+	_, err = os.client.PendingCallContract(context.Background(), msg)
+	return err
+}
+
+// classifyVoteError decodes the revert reason from the error of a vote simulation, and classifies it.
+func (os *OracleServer) classifyVoteError(err error) (voteErrorClass, string) {
+	reason, reverted := decodeRevert(os.oracleABI, err)
+	if !reverted {
+		return voteErrTransient, err.Error()
+	}
+
+	if class, ok := voteRevertReasons[reason]; ok {
+		return class, reason
+	}
+	return voteErrTransient, reason
+}
+
+// decodeRevert resolves the revert reason from the error data of a call, both the Error(string) reverts and the custom
+// errors defined in the oracle ABI are decoded. It returns false if the error is not a revert.
+func decodeRevert(oracleABI *abi.ABI, err error) (string, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return err.Error(), strings.Contains(err.Error(), "execution reverted")
+	}
+
+	hexData, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return dataErr.Error(), true
+	}
+
+	data, e := hexutil.Decode(hexData)
+	if e != nil || len(data) < 4 {
+		return dataErr.Error(), true
+	}
+
+	if reason, e := abi.UnpackRevert(data); e == nil {
+		return reason, true
+	}
+
+	for name, abiErr := range oracleABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+			continue
+		}
+		if args, e := abiErr.Unpack(data); e == nil {
+			return fmt.Sprintf("%s%v", name, args), true
+		}
+		return name, true
+	}
+	return dataErr.Error(), true
+}
@@ -0,0 +1,137 @@
+package oracleserver
+
+import (
+	"autonity-oracle/config"
+	contract "autonity-oracle/contract_binder/contract"
+	"autonity-oracle/types/mock"
+	"errors"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+)
+
+// revertError simulates the error returned by the L1 RPC on a reverted call.
+type revertError struct {
+	data string
+}
+
+func (e *revertError) Error() string          { return "execution reverted" }
+func (e *revertError) ErrorData() interface{} { return e.data }
+
+func newRevertError(t *testing.T, reason string) error {
+	strType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	packed, err := abi.Arguments{{Type: strType}}.Pack(reason)
+	require.NoError(t, err)
+	// the selector of Error(string).
+	data := append([]byte{0x08, 0xc3, 0x79, 0xa0}, packed...)
+	return &revertError{data: hexutil.Encode(data)}
+}
+
+func TestPreflightVote(t *testing.T) {
+	oracleABI, err := contract.OracleMetaData.GetAbi()
+	require.NoError(t, err)
+
+	from := common.HexToAddress("0xb749d3d83376276ab4ddef2d9300fb5ce70ebafe")
+	commit := big.NewInt(1)
+	salt := big.NewInt(100)
+	reports := []contract.IOracleReport{{Price: big.NewInt(1000), Confidence: 100}}
+
+	newServer := func(ctrl *gomock.Controller) (*OracleServer, *mock.MockBlockchain) {
+		l1Mock := mock.NewMockBlockchain(ctrl)
+		return &OracleServer{
+			logger:    hclog.NewNullLogger(),
+			conf:      &config.Config{},
+			client:    l1Mock,
+			oracleABI: oracleABI,
+		}, l1Mock
+	}
+
+	t.Run("vote passes the simulation", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srv, l1Mock := newServer(ctrl)
+		l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, nil)
+		r, s, err := srv.preflightVote(from, 3000000, commit, reports, salt)
+		require.NoError(t, err)
+		require.Equal(t, reports, r)
+		require.Equal(t, salt, s)
+	})
+
+	t.Run("retry without reveal", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srv, l1Mock := newServer(ctrl)
+		gomock.InOrder(
+			l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, newRevertError(t, "commit mismatch")),
+			l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, nil),
+		)
+		r, s, err := srv.preflightVote(from, 3000000, commit, reports, salt)
+		require.NoError(t, err)
+		require.Empty(t, r)
+		require.Equal(t, invalidSalt, s)
+	})
+
+	t.Run("abort on already voted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srv, l1Mock := newServer(ctrl)
+		l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, newRevertError(t, "already voted"))
+		_, _, err := srv.preflightVote(from, 3000000, commit, reports, salt)
+		require.ErrorIs(t, err, errVoteReverted)
+		require.ErrorContains(t, err, "already voted")
+	})
+
+	t.Run("send without simulation on unknown revert", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srv, l1Mock := newServer(ctrl)
+		l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, newRevertError(t, "salt is zero"))
+		r, s, err := srv.preflightVote(from, 3000000, commit, reports, salt)
+		require.NoError(t, err)
+		require.Equal(t, reports, r)
+		require.Equal(t, salt, s)
+	})
+
+	t.Run("send without simulation on transient error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		srv, l1Mock := newServer(ctrl)
+		l1Mock.EXPECT().PendingCallContract(gomock.Any(), gomock.Any()).Return(nil, errors.New("connection refused"))
+		r, s, err := srv.preflightVote(from, 3000000, commit, reports, salt)
+		require.NoError(t, err)
+		require.Equal(t, reports, r)
+		require.Equal(t, salt, s)
+	})
+}
+
+func TestClassifyVoteError(t *testing.T) {
+	oracleABI, err := contract.OracleMetaData.GetAbi()
+	require.NoError(t, err)
+	srv := &OracleServer{oracleABI: oracleABI}
+
+	class, reason := srv.classifyVoteError(newRevertError(t, "restricted to only voters"))
+	require.Equal(t, voteErrNotVoter, class)
+	require.Equal(t, "restricted to only voters", reason)
+
+	class, _ = srv.classifyVoteError(newRevertError(t, "already voted"))
+	require.Equal(t, voteErrAlreadyVoted, class)
+
+	// the reasons are matched exactly, an unknown revert is taken as transient.
+	class, _ = srv.classifyVoteError(newRevertError(t, "report price overflow"))
+	require.Equal(t, voteErrTransient, class)
+
+	class, _ = srv.classifyVoteError(errors.New("execution reverted"))
+	require.Equal(t, voteErrTransient, class)
+
+	class, _ = srv.classifyVoteError(errors.New("i/o timeout"))
+	require.Equal(t, voteErrTransient, class)
+	// the unknown class is not taken as any of the known ones.
+	require.Equal(t, "invalid reveal", voteErrInvalidReveal.String())
+	require.Equal(t, "unknown(9)", voteErrorClass(9).String())
+}
@@ -0,0 +1,198 @@
+package pluginprobe
+
+import (
+	"autonity-oracle/config"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	common2 "autonity-oracle/plugins/common"
+	"autonity-oracle/plugintest"
+	"autonity-oracle/types"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	defaultProbeRounds   = 3
+	defaultProbeInterval = time.Second
+
+	errMissingPluginName = errors.New("the name of the plugin to be probed is missing")
+)
+
+// ProbeConfig is the resolved configuration of a plugin probing.
+type ProbeConfig struct {
+	PluginDIR    string
+	PluginName   string
+	PluginConfig config.PluginConfig
+	LoggingLevel hclog.Level
+	ChainID      int64
+	Symbols      []string
+	Rounds       int
+	Interval     time.Duration
+}
+
+// noopSubscriber satisfies the sample event subscription of the plugin wrapper, no sampling event is ever sent, as the
+// probe drives the data fetching by itself.
+type noopSubscriber struct {
+	feed event.Feed
+}
+
+func (s *noopSubscriber) WatchSampleEvent(sink chan<- *types.SampleEvent) event.Subscription {
+	return s.feed.Subscribe(sink)
+}
+
+// Run is the entry of the plugin sub commands, it returns the exit code of the process.
+func Run(args []string, out io.Writer) int {
+	if len(args) > 0 && args[0] == "test" {
+		return plugintest.RunCLI(args[1:], out)
+	}
+
+	if len(args) == 0 || args[0] != "probe" {
+		printUsage(out)
+		plugintest.PrintUsage(out)
+		return 1
+	}
+
+	conf, err := ParseProbeConfig(args[1:])
+	if err != nil {
+		fmt.Fprintf(out, "cannot parse probe arguments: %s\n", err.Error())
+		printUsage(out)
+		return 1
+	}
+
+	if err = Probe(conf, out); err != nil {
+		fmt.Fprintf(out, "probe plugin %s failed: %s\n", conf.PluginName, err.Error())
+		return 1
+	}
+	return 0
+}
+
+// ParseProbeConfig resolves the probe configuration from the arguments: <name> [flags].
+func ParseProbeConfig(args []string) (*ProbeConfig, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return nil, errMissingPluginName
+	}
+
+	name := args[0]
+	fs := flag.NewFlagSet("probe", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	confFile := fs.String("config", "", "the oracle server config file to load the plugin directory and plugin configs from")
+	pluginDir := fs.String("plugin-dir", "", "the directory of the plugin binary, it overrides the one of the config file")
+	chainID := fs.Int64("chain-id", common2.ChainIDPiccadilly.Int64(), "the chain ID to be checked by the plugin")
+	symbols := fs.String("symbols", "", "comma separated symbols to be fetched, default to the plugin's available symbols")
+	rounds := fs.Int("n", defaultProbeRounds, "the number of data fetching rounds")
+	interval := fs.Duration("interval", defaultProbeInterval, "the interval in between the data fetching rounds")
+	logLevel := fs.Int("log-level", int(hclog.Warn), "the logging verbosity of the plugin wrapper")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+
+	conf := &ProbeConfig{
+		PluginDIR:    config.DefaultConfig.PluginDIR,
+		PluginName:   name,
+		PluginConfig: config.PluginConfig{Name: name},
+		LoggingLevel: hclog.Level(*logLevel), //nolint
+		ChainID:      *chainID,
+		Rounds:       *rounds,
+		Interval:     *interval,
+	}
+
+	if *confFile != "" {
+		serverConf, err := config.LoadServerConfig(*confFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.PluginDIR = serverConf.PluginDIR
+		for _, c := range serverConf.PluginConfigs {
+			if c.Name == name {
+				conf.PluginConfig = c
+				break
+			}
+		}
+	}
+
+	if *pluginDir != "" {
+		conf.PluginDIR = *pluginDir
+	}
+
+	if *symbols != "" {
+		for _, s := range strings.Split(*symbols, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				conf.Symbols = append(conf.Symbols, s)
+			}
+		}
+	}
+
+	if conf.Rounds <= 0 {
+		return nil, fmt.Errorf("invalid number of rounds: %d", conf.Rounds)
+	}
+
+	return conf, nil
+}
+
+// Probe launches the plugin from the plugin directory with the same wrapper used by the oracle server, it checks the
+// plugin's statement with the chain ID, and fetches the prices for a number of rounds, and prints the results into out.
+func Probe(conf *ProbeConfig, out io.Writer) error {
+	logger := config.NewLogger(&config.DefaultLogConfig, conf.PluginName, conf.LoggingLevel)
+	pw := pWrapper.NewPluginWrapper(logger, conf.PluginName, conf.PluginDIR, &noopSubscriber{}, &conf.PluginConfig, nil)
+	if err := pw.Initialize(conf.ChainID); err != nil {
+		pw.CleanPluginProcess()
+		return err
+	}
+	defer pw.Close()
+
+	state, err := pw.State(conf.ChainID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "plugin: %s, version: %s, data source: %s, source type: %d, key required: %t\n",
+		conf.PluginName, state.Version, state.DataSource, state.DataSourceType, state.KeyRequired)
+	fmt.Fprintf(out, "available symbols: %v\n", state.AvailableSymbols)
+	fmt.Fprintf(out, "protocol version: %d, streaming: %t\n", pw.ProtocolVersion(), state.Streaming)
+	if pw.ProtocolVersion() >= types.ProtocolVersionSymbolMetadata {
+		for _, meta := range state.Symbols {
+			fmt.Fprintf(out, "  symbol: %s, quote: %s, decimals: %d, update interval: %ds, market hours: %d, derived: %t\n",
+				meta.Symbol, meta.QuoteCurrency, meta.Decimals, meta.UpdateInterval, meta.MarketHours, meta.Derived)
+		}
+	}
+
+	symbols := conf.Symbols
+	if len(symbols) == 0 {
+		symbols = state.AvailableSymbols
+	}
+
+	for i := 0; i < conf.Rounds; i++ {
+		if i > 0 {
+			time.Sleep(conf.Interval)
+		}
+
+		start := time.Now()
+		report, err := pw.FetchPrices(symbols)
+		latency := time.Since(start)
+
+		fmt.Fprintf(out, "round: %d, latency: %s\n", i+1, latency)
+		if err != nil {
+			fmt.Fprintf(out, "  error: %s\n", err.Error())
+		}
+		for _, p := range report.Prices {
+			fmt.Fprintf(out, "  symbol: %s, price: %s, volume: %v, timestamp: %d\n", p.Symbol, p.Price.String(), p.Volume, p.Timestamp)
+		}
+		if len(report.UnRecognizableSymbols) != 0 {
+			fmt.Fprintf(out, "  unrecognizable symbols: %v\n", report.UnRecognizableSymbols)
+		}
+	}
+
+	return nil
+}
+
+func printUsage(out io.Writer) {
+	fmt.Fprint(out, "Usage of plugin probe:\n")
+	fmt.Fprintf(out, "%s plugin probe <name> [-config oracle_config.yml] [-plugin-dir dir] [-chain-id id] "+
+		"[-symbols A-B,C-D] [-n rounds] [-interval 1s] [-log-level 4]\n", os.Args[0])
+}
@@ -0,0 +1,58 @@
+package pluginprobe
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseProbeConfig(t *testing.T) {
+	_, err := ParseProbeConfig(nil)
+	require.ErrorIs(t, err, errMissingPluginName)
+
+	_, err = ParseProbeConfig([]string{"-n", "2"})
+	require.ErrorIs(t, err, errMissingPluginName)
+
+	conf, err := ParseProbeConfig([]string{"template_plugin", "-config", "../test_data/oracle_config.yml",
+		"-symbols", "EUR-USD, NTN-USDC", "-n", "2", "-interval", "10ms", "-chain-id", "1000"})
+	require.NoError(t, err)
+	require.Equal(t, "template_plugin", conf.PluginName)
+	require.Equal(t, "../plugins/template_plugin/bin", conf.PluginDIR)
+	require.Equal(t, []string{"EUR-USD", "NTN-USDC"}, conf.Symbols)
+	require.Equal(t, 2, conf.Rounds)
+	require.Equal(t, 10*time.Millisecond, conf.Interval)
+	require.Equal(t, int64(1000), conf.ChainID)
+
+	_, err = ParseProbeConfig([]string{"template_plugin", "-n", "0"})
+	require.Error(t, err)
+}
+
+func TestProbe(t *testing.T) {
+	conf, err := ParseProbeConfig([]string{"template_plugin", "-plugin-dir", "../plugins/template_plugin/bin",
+		"-symbols", "EUR-USD,NTN-USDC,UNKNOWN-USD", "-n", "2", "-interval", "10ms"})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, Probe(conf, &out))
+	require.Contains(t, out.String(), "plugin: template_plugin")
+	// the template plugin is served without the versioned plugins, thus it takes the base protocol.
+	require.Contains(t, out.String(), "protocol version: 1")
+	require.Contains(t, out.String(), "round: 2")
+	require.Contains(t, out.String(), "symbol: EUR-USD")
+	require.Contains(t, out.String(), "unrecognizable symbols: [UNKNOWN-USD]")
+}
+
+func TestValidateConfig(t *testing.T) {
+	var out bytes.Buffer
+	require.Equal(t, 1, ValidateConfig(nil, &out))
+	require.Contains(t, out.String(), errMissingConfigFile.Error())
+
+	out.Reset()
+	require.Equal(t, 0, ValidateConfig([]string{"../test_data/oracle_config.yml"}, &out))
+	require.Contains(t, out.String(), "plugin template_plugin: ok, no config schema is published")
+	require.Contains(t, out.String(), "config ../test_data/oracle_config.yml is valid")
+
+	out.Reset()
+	require.Equal(t, 1, ValidateConfig([]string{"../test_data/oracle_config.yml", "-chain-id", "abc"}, &out))
+}
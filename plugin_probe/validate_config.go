@@ -0,0 +1,150 @@
+package pluginprobe
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/helpers"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	common2 "autonity-oracle/plugins/common"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+var errMissingConfigFile = errors.New("the oracle server config file is missing")
+
+// ValidateConfig is the entry of the validate-config sub command, it validates the oracle server config, then it
+// launches the plugins with their plugin configs, and validates the configs against the config schemas published by
+// the plugins, thus the misconfiguration is reported before the oracle server runs into it. It returns the exit code.
+func ValidateConfig(args []string, out io.Writer) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintf(out, "cannot parse validate-config arguments: %s\n", errMissingConfigFile.Error())
+		printValidateUsage(out)
+		return 1
+	}
+
+	fs := flag.NewFlagSet("validate-config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	chainID := fs.Int64("chain-id", common2.ChainIDPiccadilly.Int64(), "the chain ID to be checked by the plugins")
+	logLevel := fs.Int("log-level", int(hclog.Error), "the logging verbosity of the plugin wrappers")
+	if err := fs.Parse(args[1:]); err != nil {
+		fmt.Fprintf(out, "cannot parse validate-config arguments: %s\n", err.Error())
+		printValidateUsage(out)
+		return 1
+	}
+
+	serverConf, err := config.LoadServerConfig(args[0])
+	if err != nil {
+		fmt.Fprintf(out, "cannot load config %s: %s\n", args[0], err.Error())
+		return 1
+	}
+
+	valid := true
+	if err = serverConf.Validate(); err != nil {
+		fmt.Fprintf(out, "config: %s\n", err.Error())
+		valid = false
+	}
+
+	confs := make(map[string]config.PluginConfig)
+	for _, c := range serverConf.PluginConfigs {
+		confs[c.Name] = c
+	}
+
+	binaries, err := helpers.ListPlugins(serverConf.PluginDIR)
+	if err != nil {
+		fmt.Fprintf(out, "cannot list plugins in %s: %s\n", serverConf.PluginDIR, err.Error())
+		return 1
+	}
+
+	names := make([]string, 0, len(binaries)+len(confs))
+	for name := range binaries {
+		names = append(names, name)
+	}
+	for name, c := range confs {
+		if _, ok := binaries[name]; !ok {
+			if !c.InProcess || !common2.IsRegistered(name) {
+				fmt.Fprintf(out, "plugin %s: warning: the plugin config is set but there is no such plugin\n", name)
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		conf, ok := confs[name]
+		if !ok {
+			conf = config.PluginConfig{Name: name}
+		}
+		if conf.Disabled {
+			fmt.Fprintf(out, "plugin %s: disabled\n", name)
+			continue
+		}
+		if !validatePlugin(serverConf, &conf, *chainID, hclog.Level(*logLevel), out) { //nolint
+			valid = false
+		}
+	}
+
+	if !valid {
+		fmt.Fprintf(out, "config %s is invalid\n", args[0])
+		return 1
+	}
+	fmt.Fprintf(out, "config %s is valid\n", args[0])
+	return 0
+}
+
+// validatePlugin launches the plugin with its config as the oracle server does, and prints the config fields of the
+// schema published by the plugin.
+func validatePlugin(serverConf *config.ServerConfig, conf *config.PluginConfig, chainID int64, level hclog.Level,
+	out io.Writer) bool {
+	logger := config.NewLogger(&config.DefaultLogConfig, conf.Name, level)
+	var pw *pWrapper.PluginWrapper
+	if conf.InProcess && common2.IsRegistered(conf.Name) {
+		pw = pWrapper.NewInProcessPluginWrapper(logger, conf.Name, &noopSubscriber{}, conf)
+	} else {
+		operatorKey, err := serverConf.IntegrityConfigs.OperatorKey()
+		if err != nil {
+			fmt.Fprintf(out, "plugin %s: %s\n", conf.Name, err.Error())
+			return false
+		}
+		secure, err := pWrapper.VerifyBinary(serverConf.PluginDIR, conf.Name, conf.Checksum, operatorKey,
+			serverConf.IntegrityConfigs.Enforce)
+		if err != nil {
+			fmt.Fprintf(out, "plugin %s: %s\n", conf.Name, err.Error())
+			return false
+		}
+		pw = pWrapper.NewPluginWrapper(logger, conf.Name, serverConf.PluginDIR, &noopSubscriber{}, conf, secure)
+	}
+
+	if err := pw.Initialize(chainID); err != nil {
+		pw.CleanPluginProcess()
+		fmt.Fprintf(out, "plugin %s: %s\n", conf.Name, err.Error())
+		return false
+	}
+	defer pw.Close()
+
+	schema := pw.ConfigSchema()
+	if len(schema) == 0 {
+		fmt.Fprintf(out, "plugin %s: ok, no config schema is published\n", conf.Name)
+		return true
+	}
+
+	fmt.Fprintf(out, "plugin %s: ok\n", conf.Name)
+	warnings, _ := conf.CheckSchema(schema)
+	for _, w := range warnings {
+		fmt.Fprintf(out, "  warning: %s\n", w)
+	}
+	for _, f := range schema {
+		fmt.Fprintf(out, "  %s: %q, required: %t, %s\n", f.Name, conf.SchemaValue(f), f.Required, f.Description)
+	}
+	return true
+}
+
+func printValidateUsage(out io.Writer) {
+	fmt.Fprint(out, "Usage of validate-config:\n")
+	fmt.Fprintf(out, "%s validate-config <oracle_config.yml> [-chain-id id] [-log-level 5]\n", os.Args[0])
+}
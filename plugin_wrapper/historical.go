@@ -0,0 +1,100 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"context"
+)
+
+var missedTargetDistance = int64(2) // a sample farther than 2s from the target of a round missed the target.
+
+// Historical returns true if the plugin can fetch the prices at a given time.
+func (pw *PluginWrapper) Historical() bool {
+	return pw.historical
+}
+
+// missedTarget returns the symbols whose nearest samples are farther than the missedTargetDistance from the target,
+// including the symbols without any sample.
+func (pw *PluginWrapper) missedTarget(symbols []string, target int64) []string {
+	pw.lockSamples.RLock()
+	defer pw.lockSamples.RUnlock()
+
+	var missed []string
+	for _, s := range symbols {
+		hit := false
+		for ts := range pw.samples[s] {
+			if ts-target <= missedTargetDistance && target-ts <= missedTargetDistance {
+				hit = true
+				break
+			}
+		}
+		if !hit {
+			missed = append(missed, s)
+		}
+	}
+	return missed
+}
+
+// BackfillTarget fetches the prices at the target timestamp of the symbols whose samples missed the target, and the
+// fetched prices are sampled at the target, thus they are taken by the aggregation of the round. It is for CEX plugins
+// only, as the samples of AMMs and AFQs are aggregated with VWAP over the pre-sampling period.
+//
+// The backfill is bounded by the context instead of the fetch deadline, as the vote waits for it. It is skipped if the
+// fetch abandoned on the deadline or the previous backfill is still pending, and the backfill abandoned on the context
+// does not block the fetches of the plugin, as it is called without the lockService held.
+func (pw *PluginWrapper) BackfillTarget(ctx context.Context, symbols []string, target int64) error {
+	if !pw.historical || pw.dataSrcType != types.SrcCEX {
+		return nil
+	}
+
+	missed := pw.missedTarget(symbols, target)
+	if len(missed) == 0 {
+		return nil
+	}
+
+	done := make(chan fetchResult, 1)
+	go func() {
+		report, err := pw.fetchPriceAt(missed, target)
+		done <- fetchResult{report: report, err: err}
+	}()
+
+	var r fetchResult
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case r = <-done:
+	}
+	if r.err != nil {
+		return r.err
+	}
+
+	if len(r.report.Prices) > 0 {
+		pw.logger.Debug("backfilled samples", "target", target, "data points", r.report.Prices)
+		pw.AddSample(r.report.Prices, target)
+	}
+	return nil
+}
+
+// fetchPriceAt calls the historical fetcher of the plugin, it returns ErrPluginBusy if there is a pending call.
+func (pw *PluginWrapper) fetchPriceAt(symbols []string, target int64) (types.PluginPriceReport, error) {
+	if pw.busy() || !pw.backfilling.CompareAndSwap(false, true) {
+		return types.PluginPriceReport{}, types.ErrPluginBusy
+	}
+	defer pw.backfilling.Store(false)
+	return pw.adapter.(types.HistoricalFetcher).FetchPriceAt(symbols, target)
+}
+
+// busy returns true if the fetch abandoned on the deadline is still pending.
+func (pw *PluginWrapper) busy() bool {
+	pw.lockService.Lock()
+	defer pw.lockService.Unlock()
+	if pw.abandoned == nil {
+		return false
+	}
+	select {
+	case <-pw.abandoned:
+		pw.abandoned = nil
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,49 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/hashicorp/go-hclog"
+	"time"
+)
+
+// NewInProcessPluginWrapper creates the wrapper of a plugin registered by plugins/common.RegisterAdapter, the adapter
+// runs in the oracle server process, thus the integrity verification and the resource limits are not applied to it.
+func NewInProcessPluginWrapper(logger hclog.Logger, name string, sub types.SampleEventSubscriber,
+	conf *config.PluginConfig) *PluginWrapper {
+	return &PluginWrapper{
+		name:             name,
+		inProcess:        true,
+		conf:             conf,
+		samplingSub:      sub,
+		startAt:          time.Now(),
+		doneCh:           make(chan struct{}),
+		samples:          make(map[string]map[int64]types.Price),
+		latestTimestamps: make(map[string]int64),
+		symbolMeta:       make(map[string]types.SymbolMetadata),
+		chSampleEvent:    make(chan *types.SampleEvent),
+		priceMetrics:     make(map[string]metrics.GaugeFloat64),
+		forwarded:        newForwardedMetrics(),
+		reliability:      newReliability(name),
+		scheduler:        newFetchScheduler(name),
+		logger:           logger,
+	}
+}
+
+// InProcess returns true if the plugin runs in the oracle server process.
+func (pw *PluginWrapper) InProcess() bool {
+	return pw.inProcess
+}
+
+// kill stops the plugin process, or it closes the in-process adapter.
+func (pw *PluginWrapper) kill() {
+	if !pw.inProcess {
+		pw.plugin.Kill()
+		return
+	}
+
+	if closer, ok := pw.adapter.(interface{ Close() }); ok {
+		closer.Close()
+	}
+}
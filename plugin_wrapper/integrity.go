@@ -0,0 +1,67 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-plugin"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SignatureSuffix is the file suffix of the detached signature of a plugin binary, the signature of the plugin
+// "crypto_uniswap" is read from the file "crypto_uniswap.sig" in the plugin directory.
+const SignatureSuffix = ".sig"
+
+// VerifyBinary verifies the plugin binary against the SHA-256 checksum pinned in its config, and against its detached
+// ed25519 signature if the operator key is set. It returns the SecureConfig with the verified checksum, thus go-plugin
+// checks it again on the launch of the process. An unknown binary, which is neither pinned nor signed, is refused if the
+// verification is enforced, otherwise a nil SecureConfig is returned to launch it without the check.
+func VerifyBinary(pluginDir, name, checksum string, operatorKey ed25519.PublicKey, enforce bool) (*plugin.SecureConfig, error) {
+	path := filepath.Join(pluginDir, name)
+	binary, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(binary)
+
+	verified := false
+	if checksum != "" {
+		pinned, err := hex.DecodeString(strings.TrimPrefix(checksum, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid checksum of plugin %s: %s", types.ErrPluginIntegrity, name, err.Error())
+		}
+		if !bytes.Equal(pinned, sum[:]) {
+			return nil, fmt.Errorf("%w: checksum mismatch of plugin %s, pinned: %x, binary: %x", types.ErrPluginIntegrity,
+				name, pinned, sum)
+		}
+		verified = true
+	}
+
+	if operatorKey != nil {
+		signature, err := os.ReadFile(path + SignatureSuffix)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+		case err != nil:
+			return nil, err
+		case !ed25519.Verify(operatorKey, binary, signature):
+			return nil, fmt.Errorf("%w: invalid signature of plugin %s", types.ErrPluginIntegrity, name)
+		default:
+			verified = true
+		}
+	}
+
+	if !verified {
+		if enforce {
+			return nil, fmt.Errorf("%w: plugin %s is neither pinned by a checksum nor signed", types.ErrPluginIntegrity, name)
+		}
+		return nil, nil
+	}
+
+	return &plugin.SecureConfig{Checksum: sum[:], Hash: sha256.New()}, nil
+}
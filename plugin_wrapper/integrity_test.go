@@ -0,0 +1,58 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyBinary(t *testing.T) {
+	dir := t.TempDir()
+	binary := []byte("plugin binary")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin"), binary, 0700)) //nolint
+	sum := sha256.Sum256(binary)
+	checksum := hex.EncodeToString(sum[:])
+
+	operatorKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	t.Run("test unknown binary", func(t *testing.T) {
+		secure, err := VerifyBinary(dir, "plugin", "", operatorKey, false)
+		require.NoError(t, err)
+		require.Nil(t, secure)
+
+		_, err = VerifyBinary(dir, "plugin", "", operatorKey, true)
+		require.ErrorIs(t, err, types.ErrPluginIntegrity)
+	})
+
+	t.Run("test pinned checksum", func(t *testing.T) {
+		secure, err := VerifyBinary(dir, "plugin", checksum, nil, true)
+		require.NoError(t, err)
+		require.Equal(t, sum[:], secure.Checksum)
+
+		wrong := sha256.Sum256([]byte("modified binary"))
+		_, err = VerifyBinary(dir, "plugin", hex.EncodeToString(wrong[:]), nil, false)
+		require.ErrorIs(t, err, types.ErrPluginIntegrity)
+	})
+
+	t.Run("test detached signature", func(t *testing.T) {
+		sigFile := filepath.Join(dir, "plugin"+SignatureSuffix)
+		require.NoError(t, os.WriteFile(sigFile, ed25519.Sign(privateKey, binary), 0600))
+		defer os.Remove(sigFile)
+
+		secure, err := VerifyBinary(dir, "plugin", "", operatorKey, true)
+		require.NoError(t, err)
+		require.Equal(t, sum[:], secure.Checksum)
+
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		_, err = VerifyBinary(dir, "plugin", "", otherKey, true)
+		require.ErrorIs(t, err, types.ErrPluginIntegrity)
+	})
+}
@@ -0,0 +1,46 @@
+package pluginwrapper
+
+// The resource limits that a plugin process can breach.
+const (
+	LimitMemory  = "memory"
+	LimitCPUTime = "cpu time"
+)
+
+// applyLimits applies the resource limits of the plugin config on the started plugin process.
+func (pw *PluginWrapper) applyLimits() error {
+	if pw.cmd.Process == nil {
+		return nil
+	}
+	return applyLimits(pw.cmd.Process.Pid, &pw.conf.Limits)
+}
+
+// CheckLimits checks the plugin process against its resource limits, the process is killed once its resident memory
+// exceeds the limit. It returns the breached limit, either by the running process or by the exited one, or an empty
+// string if there is no breach.
+func (pw *PluginWrapper) CheckLimits() string {
+	if pw.inProcess || pw.breach != "" {
+		return pw.breach
+	}
+
+	if pw.plugin.Exited() {
+		pw.breach = exitBreach(pw.cmd.ProcessState, &pw.conf.Limits)
+		return pw.breach
+	}
+
+	if pw.conf.Limits.MaxRSS == 0 || pw.cmd.Process == nil {
+		return ""
+	}
+
+	rss, err := residentMemory(pw.cmd.Process.Pid)
+	if err != nil {
+		pw.logger.Debug("cannot read the resident memory of plugin process", "error", err.Error())
+		return ""
+	}
+
+	if rss > int64(pw.conf.Limits.MaxRSS)<<20 {
+		pw.logger.Warn("plugin exceeded its memory limit, killing it", "rss", rss, "limit", pw.conf.Limits.MaxRSS<<20)
+		pw.breach = LimitMemory
+		pw.plugin.Kill()
+	}
+	return pw.breach
+}
@@ -0,0 +1,116 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+	"bufio"
+	"fmt"
+	"golang.org/x/sys/unix"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const cgroupCPUPeriod = 100000 // the period in microseconds of the cgroup CPU quota.
+
+// setCredential runs the plugin process as the user and the group of the limits.
+func setCredential(cmd *exec.Cmd, limits *config.ResourceLimits) {
+	if limits.UID == 0 {
+		return
+	}
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Credential: &syscall.Credential{Uid: uint32(limits.UID), Gid: uint32(limits.GID)}, //nolint
+	}
+}
+
+// applyLimits applies the rlimits on the started plugin process, and places it in its cgroup with the quotas.
+func applyLimits(pid int, limits *config.ResourceLimits) error {
+	if limits.MaxOpenFiles > 0 {
+		n := uint64(limits.MaxOpenFiles) //nolint
+		if err := unix.Prlimit(pid, unix.RLIMIT_NOFILE, &unix.Rlimit{Cur: n, Max: n}, nil); err != nil {
+			return fmt.Errorf("cannot limit the open files: %w", err)
+		}
+	}
+
+	// the process is killed by the kernel once the CPU time reaches the hard limit.
+	if limits.MaxCPUTime > 0 {
+		n := uint64(limits.MaxCPUTime) //nolint
+		if err := unix.Prlimit(pid, unix.RLIMIT_CPU, &unix.Rlimit{Cur: n, Max: n}, nil); err != nil {
+			return fmt.Errorf("cannot limit the cpu time: %w", err)
+		}
+	}
+
+	if limits.Cgroup == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(limits.Cgroup, 0755); err != nil { //nolint
+		return fmt.Errorf("cannot create cgroup: %w", err)
+	}
+	if limits.CPUQuota > 0 {
+		quota := fmt.Sprintf("%d %d", int64(limits.CPUQuota*cgroupCPUPeriod), cgroupCPUPeriod)
+		if err := writeCgroup(limits.Cgroup, "cpu.max", quota); err != nil {
+			return err
+		}
+	}
+	if limits.MaxRSS > 0 {
+		if err := writeCgroup(limits.Cgroup, "memory.max", strconv.Itoa(limits.MaxRSS<<20)); err != nil {
+			return err
+		}
+	}
+	return writeCgroup(limits.Cgroup, "cgroup.procs", strconv.Itoa(pid))
+}
+
+func writeCgroup(cgroup, file, value string) error {
+	if err := os.WriteFile(filepath.Join(cgroup, file), []byte(value), 0644); err != nil { //nolint
+		return fmt.Errorf("cannot write %s of cgroup %s: %w", file, cgroup, err)
+	}
+	return nil
+}
+
+// residentMemory returns the resident memory in bytes of the process.
+func residentMemory(pid int) (int64, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// the line is in format of "VmRSS:	   12345 kB"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == "VmRSS:" {
+			kb, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return kb << 10, nil
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("VmRSS is not found in the status of process %d", pid)
+}
+
+// exitBreach returns the limit that the exited process was killed for by the kernel, it is judged from the resource
+// usage of the process as the kernel kills it with SIGKILL on both the CPU time and the cgroup memory limits.
+func exitBreach(state *os.ProcessState, limits *config.ResourceLimits) string {
+	if state == nil {
+		return ""
+	}
+
+	if limits.MaxCPUTime > 0 && state.UserTime()+state.SystemTime() >= time.Duration(limits.MaxCPUTime)*time.Second {
+		return LimitCPUTime
+	}
+
+	// the max RSS of the rusage is in kilobytes on Linux.
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok && limits.MaxRSS > 0 && usage.Maxrss >= int64(limits.MaxRSS)<<10 {
+		return LimitMemory
+	}
+	return ""
+}
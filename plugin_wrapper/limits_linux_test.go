@@ -0,0 +1,57 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+	common2 "autonity-oracle/plugins/common"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResourceLimits(t *testing.T) {
+	t.Run("test rlimits of plugin process", func(t *testing.T) {
+		conf := &config.PluginConfig{
+			Name:   "template_plugin",
+			Limits: config.ResourceLimits{MaxOpenFiles: 128, MaxCPUTime: 600},
+		}
+		pw := NewPluginWrapper(hclog.NewNullLogger(), "template_plugin", "../plugins/template_plugin/bin", &testSubscriber{}, conf, nil)
+		require.NoError(t, pw.Initialize(common2.ChainIDPiccadilly.Int64()))
+		defer pw.Close()
+
+		limits, err := os.ReadFile(fmt.Sprintf("/proc/%d/limits", pw.cmd.Process.Pid))
+		require.NoError(t, err)
+		for _, line := range strings.Split(string(limits), "\n") {
+			fields := strings.Fields(line)
+			switch {
+			case strings.HasPrefix(line, "Max open files"):
+				require.Equal(t, []string{"128", "128"}, fields[3:5])
+			case strings.HasPrefix(line, "Max cpu time"):
+				require.Equal(t, []string{"600", "600"}, fields[3:5])
+			}
+		}
+		require.Equal(t, "", pw.CheckLimits())
+	})
+
+	t.Run("test plugin process is killed on memory limit breach", func(t *testing.T) {
+		conf := &config.PluginConfig{
+			Name:   "template_plugin",
+			Limits: config.ResourceLimits{MaxRSS: 1},
+		}
+		pw := NewPluginWrapper(hclog.NewNullLogger(), "template_plugin", "../plugins/template_plugin/bin", &testSubscriber{}, conf, nil)
+		require.NoError(t, pw.Initialize(common2.ChainIDPiccadilly.Int64()))
+		defer pw.Close()
+
+		require.Equal(t, LimitMemory, pw.CheckLimits())
+		require.Eventually(t, pw.Exited, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("test resident memory", func(t *testing.T) {
+		rss, err := residentMemory(os.Getpid())
+		require.NoError(t, err)
+		require.Greater(t, rss, int64(0))
+	})
+}
@@ -0,0 +1,26 @@
+//go:build !linux
+
+package pluginwrapper
+
+import (
+	"autonity-oracle/config"
+	"errors"
+	"os"
+	"os/exec"
+)
+
+// the resource limits are applied on Linux only.
+
+func setCredential(_ *exec.Cmd, _ *config.ResourceLimits) {}
+
+func applyLimits(_ int, _ *config.ResourceLimits) error {
+	return nil
+}
+
+func residentMemory(_ int) (int64, error) {
+	return 0, errors.New("the resident memory is not supported on this platform")
+}
+
+func exitBreach(_ *os.ProcessState, _ *config.ResourceLimits) string {
+	return ""
+}
@@ -0,0 +1,97 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"regexp"
+	"strings"
+)
+
+// maxForwardedMetrics is the number of the distinct plugin-side metrics registered per plugin, it prevents a plugin from
+// flooding the registry of the oracle server.
+const maxForwardedMetrics = 128
+
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_.\-]+(/[a-zA-Z0-9_.\-]+)*$`)
+
+// forwardedCounter is a plugin-side counter, the plugin reports its cumulative count, thus the increments are taken by
+// the last count reported.
+type forwardedCounter struct {
+	counter metrics.Counter
+	last    int64
+}
+
+// forwardedMetrics are the plugin-side metrics registered under the plugin's namespace: oracle/<plugin>/plugin/<name>.
+type forwardedMetrics struct {
+	counters map[string]*forwardedCounter
+	gauges   map[string]metrics.GaugeFloat64
+}
+
+func newForwardedMetrics() *forwardedMetrics {
+	return &forwardedMetrics{
+		counters: make(map[string]*forwardedCounter),
+		gauges:   make(map[string]metrics.GaugeFloat64),
+	}
+}
+
+// forwardMetrics registers the plugin-side metrics carried by a price report into the metrics registry.
+func (pw *PluginWrapper) forwardMetrics(ms []types.Metric) {
+	pw.lockMetrics.Lock()
+	defer pw.lockMetrics.Unlock()
+	fm := pw.forwarded
+	for _, m := range ms {
+		if !metricNamePattern.MatchString(m.Name) {
+			pw.logger.Debug("invalid plugin metric name", "name", m.Name)
+			continue
+		}
+
+		name := strings.Join([]string{"oracle", pw.name, "plugin", m.Name}, "/")
+		switch m.Type {
+		case types.MetricCounter:
+			c, ok := fm.counters[m.Name]
+			if !ok {
+				if len(fm.counters)+len(fm.gauges) >= maxForwardedMetrics {
+					continue
+				}
+				c = &forwardedCounter{counter: metrics.GetOrRegisterCounter(name, nil)}
+				fm.counters[m.Name] = c
+			}
+			count := int64(m.Value)
+			// the count goes back once the plugin process is restarted, then it counts from zero.
+			if count < c.last {
+				c.last = 0
+			}
+			c.counter.Inc(count - c.last)
+			c.last = count
+		case types.MetricGauge:
+			g, ok := fm.gauges[m.Name]
+			if !ok {
+				if len(fm.counters)+len(fm.gauges) >= maxForwardedMetrics {
+					continue
+				}
+				g = metrics.GetOrRegisterGaugeFloat64(name, nil)
+				fm.gauges[m.Name] = g
+			}
+			g.Update(m.Value)
+		}
+	}
+}
+
+// PollMetrics polls the plugin-side metrics of the plugin which reports them on demand, thus the metrics are exported
+// even if the plugin fails to fetch prices or it is streaming. The poll is issued from a routine without the lockService
+// held, thus it neither waits for nor blocks the fetches, and it is skipped if the previous poll is still in-flight.
+func (pw *PluginWrapper) PollMetrics() {
+	if !metrics.Enabled || !pw.reportsMetrics || !pw.pollingMetrics.CompareAndSwap(false, true) {
+		return
+	}
+
+	reporter := pw.adapter.(types.MetricsReporter)
+	go func() {
+		defer pw.pollingMetrics.Store(false)
+		ms, err := reporter.ReportMetrics()
+		if err != nil {
+			pw.logger.Debug("cannot poll plugin metrics", "error", err.Error())
+			return
+		}
+		pw.forwardMetrics(ms)
+	}()
+}
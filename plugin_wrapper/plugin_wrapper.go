@@ -3,7 +3,12 @@ package pluginwrapper
 import (
 	"autonity-oracle/config"
 	"autonity-oracle/helpers"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/tracing"
 	"autonity-oracle/types"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/event"
@@ -11,16 +16,20 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"math/big"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 var (
-	sampleTTL = 30 // 30s, the TTL of a sample before GC it.
+	sampleTTL   = config.SampleTTL // 30s, the TTL of a sample before GC it.
+	staleFactor = 3                // the number of update intervals of a symbol, after that its price is stale while the market is open.
 )
 
 // PluginWrapper is the unified wrapper for the interface of a plugin, it contains metadata of a corresponding
@@ -29,58 +38,91 @@ type PluginWrapper struct {
 	version          string
 	conf             *config.PluginConfig
 	dataSrcType      types.DataSourceType
+	protocolVersion  int
+	symbolMeta       map[string]types.SymbolMetadata // the metadata of symbols, it is empty for the legacy plugins.
 	lockService      sync.RWMutex
 	lockSamples      sync.RWMutex
 	samples          map[string]map[int64]types.Price
 	latestTimestamps map[string]int64 // to track latest timestamps of samples
 
 	plugin  *plugin.Client
+	cmd     *exec.Cmd // the command of the plugin process, the resource limits are applied on it.
+	breach  string    // the resource limit breached by the plugin process.
 	adapter types.Adapter
-	name    string
-	startAt time.Time
-	logger  hclog.Logger
+
+	scheduler *fetchScheduler    // the scheduler of the sample events, there is at most one in-flight fetch.
+	abandoned <-chan fetchResult // the result of the fetch that was abandoned on the deadline.
+	name      string
+	startAt   time.Time
+	logger    hclog.Logger
 
 	doneCh         chan struct{}
 	chSampleEvent  chan *types.SampleEvent
 	subSampleEvent event.Subscription
 	samplingSub    types.SampleEventSubscriber
 
+	// the price stream of the plugin, it is only accessed by the main loop.
+	streaming   bool
+	stream      *priceStream
+	subscribing atomic.Bool // a subscription RPC is in-flight.
+
+	// the plugin runs in the oracle server process, there is neither a plugin process nor a go-plugin client.
+	inProcess bool
+
+	// the plugin can fetch the prices at a given time to backfill the samples that missed the target.
+	historical  bool
+	backfilling atomic.Bool // a backfill is pending, the next one is skipped until it returns.
+
+	// the plugin reports its metrics on demand, they are polled on the regular ticker of the oracle server.
+	reportsMetrics bool
+	pollingMetrics atomic.Bool // a poll of the metrics is in-flight.
+
+	// the schema of the plugin config fields used by the plugin, it is empty if the plugin does not publish one.
+	configSchema []types.ConfigField
+
+	// the rolling statistics of the plugin to weight its prices in the aggregation.
+	reliability *Reliability
+
 	// metrics for the prices that are sampled by per plugin.
+	lockMetrics  sync.Mutex
 	priceMetrics map[string]metrics.GaugeFloat64
+	forwarded    *forwardedMetrics // the plugin-side metrics carried by the price reports.
 }
 
-func NewPluginWrapper(logLevel hclog.Level, name string, pluginDir string, sub types.SampleEventSubscriber, conf *config.PluginConfig) *PluginWrapper {
-	// Create a hclog.Logger
-	logger := hclog.New(&hclog.LoggerOptions{
-		Name:   name,
-		Output: os.Stdout,
-		Level:  logLevel,
-	})
-
-	// pluginMap is the map of plugins we can dispense.
-	var pluginMap = map[string]plugin.Plugin{
-		"adapter": &types.AdapterPlugin{},
-	}
+// NewPluginWrapper creates the wrapper of a plugin, the logger is shared with the go-plugin client, thus the logs
+// forwarded from the plugin process are filtered and formatted by it as well. The binary's checksum is checked on the
+// launch if the SecureConfig is set, please refer to VerifyBinary.
+func NewPluginWrapper(logger hclog.Logger, name string, pluginDir string, sub types.SampleEventSubscriber,
+	conf *config.PluginConfig, secure *plugin.SecureConfig) *PluginWrapper {
+	cmd := exec.Command(fmt.Sprintf("%s/%s", pluginDir, name)) //nolint
+	setCredential(cmd, &conf.Limits)
 
 	// We're a host! Create the plugin life cycle object with configuration
 	pg := plugin.NewClient(&plugin.ClientConfig{
-		HandshakeConfig: types.HandshakeConfig,
-		Plugins:         pluginMap,
-		Cmd:             exec.Command(fmt.Sprintf("%s/%s", pluginDir, name)), //nolint
-		Logger:          logger,
+		HandshakeConfig:  types.HandshakeConfig,
+		VersionedPlugins: types.VersionedPlugins(nil),
+		Cmd:              cmd,
+		SecureConfig:     secure,
+		Logger:           logger,
+		AllowedProtocols: types.AllowedProtocols,
 	})
 
 	p := &PluginWrapper{
 		name:             name,
 		plugin:           pg,
+		cmd:              cmd,
 		conf:             conf,
 		samplingSub:      sub,
 		startAt:          time.Now(),
 		doneCh:           make(chan struct{}),
 		samples:          make(map[string]map[int64]types.Price),
 		latestTimestamps: make(map[string]int64),
+		symbolMeta:       make(map[string]types.SymbolMetadata),
 		chSampleEvent:    make(chan *types.SampleEvent),
 		priceMetrics:     make(map[string]metrics.GaugeFloat64),
+		forwarded:        newForwardedMetrics(),
+		reliability:      newReliability(name),
+		scheduler:        newFetchScheduler(name),
 		logger:           logger,
 	}
 
@@ -110,6 +152,14 @@ func (pw *PluginWrapper) AddSample(prices []types.Price, ts int64) {
 // while for data points from CEX, the last sample of the pre-sampling period will be taken.
 // The target is the timestamp on which the round block is mined, it's used to select datapoint from CEX data source.
 func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Price, error) {
+	price, err := pw.aggregatedPrice(symbol, target)
+	if err == nil || errors.Is(err, types.ErrStalePrice) {
+		pw.reliability.ObserveStaleness(err != nil)
+	}
+	return price, err
+}
+
+func (pw *PluginWrapper) aggregatedPrice(symbol string, target int64) (types.Price, error) {
 	pw.lockSamples.RLock()
 	defer pw.lockSamples.RUnlock()
 	tsMap, ok := pw.samples[symbol]
@@ -123,17 +173,24 @@ func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Pri
 		var prices []decimal.Decimal
 		var volumes []*big.Int
 		for _, sample := range tsMap {
+			if pw.isStale(symbol, sample.Timestamp, target) {
+				continue
+			}
 			prices = append(prices, sample.Price)
 			volumes = append(volumes, sample.Volume)
 		}
 
+		if len(prices) == 0 {
+			return types.Price{}, types.ErrStalePrice
+		}
+
 		vwap, highestVol, err := helpers.VWAP(prices, volumes)
 		if err != nil {
-			pw.logger.Error("failed to calculate vwap", "symbol", symbol, "err", err)
+			pw.logger.Error("failed to calculate vwap", config.LogKeySymbol, symbol, "err", err)
 			return types.Price{}, err
 		}
 
-		pw.logger.Debug("VWAP aggregation", "symbol", symbol, "samples", len(tsMap), "vwap", vwap.String())
+		pw.logger.Debug("VWAP aggregation", config.LogKeySymbol, symbol, "samples", len(tsMap), "vwap", vwap.String())
 		return types.Price{Symbol: symbol, Price: vwap, Timestamp: target, Volume: highestVol}, nil
 	}
 
@@ -141,13 +198,13 @@ func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Pri
 	// Short-circuit if there's only one sample of data points from CEX
 	if len(tsMap) == 1 {
 		for _, price := range tsMap {
-			return price, nil // Return the only sample
+			return pw.checkStaleness(price, target) // Return the only sample
 		}
 	}
 
 	// Try to get the target TS sample, otherwise we search for the nearest measurement.
 	if p, ok := tsMap[target]; ok {
-		return p, nil
+		return pw.checkStaleness(p, target)
 	}
 
 	var nearestKey int64
@@ -165,10 +222,28 @@ func (pw *PluginWrapper) AggregatedPrice(symbol string, target int64) (types.Pri
 	}
 
 	price := tsMap[nearestKey]
-	pw.logger.Debug("nearest sample", "symbol", symbol, "samples", len(tsMap), "targetTS", target, "nearestTS", nearestKey, "price", price)
+	pw.logger.Debug("nearest sample", config.LogKeySymbol, symbol, "samples", len(tsMap), "targetTS", target, "nearestTS", nearestKey, "price", price)
+	return pw.checkStaleness(price, target)
+}
+
+func (pw *PluginWrapper) checkStaleness(price types.Price, target int64) (types.Price, error) {
+	if pw.isStale(price.Symbol, price.Timestamp, target) {
+		pw.logger.Debug("stale sample", config.LogKeySymbol, price.Symbol, "targetTS", target, "sampleTS", price.Timestamp)
+		return types.Price{}, types.ErrStalePrice
+	}
 	return price, nil
 }
 
+// isStale checks the timestamp of a sample with the update interval of the symbol stated by the plugin, a sample is
+// never stale if the market is closed, or if the plugin does not state the update interval of the symbol.
+func (pw *PluginWrapper) isStale(symbol string, ts int64, target int64) bool {
+	meta, ok := pw.symbolMeta[symbol]
+	if !ok || meta.UpdateInterval <= 0 || !meta.MarketHours.IsOpen(time.Unix(target, 0)) {
+		return false
+	}
+	return target-ts > int64(staleFactor)*meta.UpdateInterval+int64(sampleTTL)
+}
+
 // GCExpiredSamples removes data points that are older than the TTL seconds of per plugin, it leaves recent samples
 // together with next round's pre-samples as the input for the price aggregation for AMM, AFQ plugins. While, for CEX
 // plugins, only the latest sample are kept without GC.
@@ -221,24 +296,38 @@ func (pw *PluginWrapper) StartTime() time.Time {
 	return pw.startAt
 }
 
+// Reliability returns the rolling statistics of the plugin.
+func (pw *PluginWrapper) Reliability() *Reliability {
+	return pw.reliability
+}
+
+// ProtocolVersion returns the plugin protocol version negotiated with the plugin.
+func (pw *PluginWrapper) ProtocolVersion() int {
+	return pw.protocolVersion
+}
+
+// ConfigSchema returns the schema of the plugin config fields published by the plugin.
+func (pw *PluginWrapper) ConfigSchema() []types.ConfigField {
+	return pw.configSchema
+}
+
+// SymbolMetadata returns the metadata of a symbol stated by the plugin, it is not available for the legacy plugins.
+func (pw *PluginWrapper) SymbolMetadata(symbol string) (types.SymbolMetadata, bool) {
+	meta, ok := pw.symbolMeta[symbol]
+	return meta, ok
+}
+
 // Initialize start the plugin, connect to it and do a handshake via state() interface.
 func (pw *PluginWrapper) Initialize(chainID int64) error {
-	// start the plugin process and connect to it
-	rpcClient, err := pw.plugin.Client()
-	if err != nil {
-		pw.logger.Error("cannot start plugin process", "error", err.Error())
+	if err := pw.conf.Limits.Validate(); err != nil {
+		pw.logger.Error("invalid resource limits", "error", err.Error())
 		return err
 	}
 
-	// dispenses a new instance of the plugin
-	raw, err := rpcClient.Dispense("adapter")
-	if err != nil {
-		pw.logger.Error("cannot dispense adapter", "error", err.Error())
+	if err := pw.launch(); err != nil {
 		return err
 	}
 
-	pw.adapter = raw.(types.Adapter)
-
 	// load with plugin's statement, check if chainID is matched.
 	state, err := pw.state(chainID)
 	if err != nil {
@@ -247,22 +336,100 @@ func (pw *PluginWrapper) Initialize(chainID int64) error {
 	}
 	pw.dataSrcType = state.DataSourceType
 	pw.version = state.Version
+	pw.protocolVersion = pw.negotiatedVersion()
+	if pw.protocolVersion >= types.ProtocolVersionSymbolMetadata {
+		for _, meta := range state.Symbols {
+			pw.symbolMeta[meta.Symbol] = meta
+		}
+	}
 	if state.KeyRequired && pw.conf.Key == "" {
 		return types.ErrMissingServiceKey
 	}
+	pw.configSchema = state.ConfigSchema
+	warnings, err := pw.conf.CheckSchema(state.ConfigSchema)
+	for _, w := range warnings {
+		pw.logger.Warn("plugin config", "warning", w)
+	}
+	if err != nil {
+		pw.logger.Error("invalid plugin config", "error", err.Error())
+		return err
+	}
+	_, canStream := pw.adapter.(types.PriceStreamer)
+	pw.streaming = state.Streaming && canStream
+	_, canFetchAt := pw.adapter.(types.HistoricalFetcher)
+	pw.historical = state.Historical && canFetchAt
+	_, canReport := pw.adapter.(types.MetricsReporter)
+	pw.reportsMetrics = state.ReportsMetrics && canReport
 
 	// all good, start to subscribe data sampling event from oracle server, and listen for sampling.
 	go pw.start()
-	pw.logger.Info("plugin is up and running", "name", pw.name, "state", state)
+	pw.logger.Info("plugin is up and running", "name", pw.name, "protocol", pw.protocolVersion, "state", state)
 	return nil
 }
 
+// launch starts the plugin process and dispenses the adapter from it, or it creates the adapter in process.
+func (pw *PluginWrapper) launch() error {
+	if pw.inProcess {
+		adapter, err := common.NewInProcessAdapter(pw.name, pw.conf)
+		if err != nil {
+			pw.logger.Error("cannot create in-process adapter", "error", err.Error())
+			return err
+		}
+		pw.adapter = adapter
+		return nil
+	}
+
+	confFile, err := pw.applyPluginConf()
+	if err != nil {
+		pw.logger.Error("cannot apply plugin configuration", "error", err.Error())
+		return err
+	}
+	// the plugin loads its configuration on startup, that is before the handshake is done.
+	defer os.Remove(confFile)
+
+	// start the plugin process and connect to it
+	rpcClient, err := pw.plugin.Client()
+	if err != nil {
+		pw.logger.Error("cannot start plugin process", "error", err.Error())
+		return err
+	}
+
+	if err = pw.applyLimits(); err != nil {
+		pw.logger.Error("cannot apply resource limits", "error", err.Error())
+		return err
+	}
+
+	// dispenses a new instance of the plugin
+	raw, err := rpcClient.Dispense("adapter")
+	if err != nil {
+		pw.logger.Error("cannot dispense adapter", "error", err.Error())
+		return err
+	}
+
+	pw.adapter = raw.(types.Adapter)
+	return nil
+}
+
+// negotiatedVersion returns the protocol version negotiated with the plugin process, the in-process plugins are built
+// with the latest protocol.
+func (pw *PluginWrapper) negotiatedVersion() int {
+	if pw.inProcess {
+		return types.ProtocolVersionSymbolMetadata
+	}
+	return pw.plugin.NegotiatedVersion()
+}
+
 func (pw *PluginWrapper) Exited() bool {
+	if pw.inProcess {
+		return false
+	}
 	return pw.plugin.Exited()
 }
 
 func (pw *PluginWrapper) start() {
 	pw.subSampleEvent = pw.samplingSub.WatchSampleEvent(pw.chSampleEvent)
+	go pw.runFetcher()
+	defer pw.scheduler.stop()
 	for {
 		select {
 		case <-pw.doneCh:
@@ -273,19 +440,41 @@ func (pw *PluginWrapper) start() {
 				pw.logger.Error("plugin wrapper main loop", "error", err.Error())
 			}
 			return
+		case prices := <-pw.streamCh():
+			// the pushed prices are sampled at the time they arrive, as they are the live prices of the data source.
+			now := time.Now()
+			pw.AddSample(prices, now.Unix())
+			// a push is observed as a fetch, thus a streaming plugin is scored as well as the polled ones.
+			pw.reliability.ObserveFetch(nil, pushLatency(prices, now))
+			if metrics.Enabled {
+				pw.updateMetrics(prices)
+			}
 		case sampleEvent := <-pw.chSampleEvent:
-			pw.logger.Debug("sampling price", "symbols", sampleEvent.Symbols, "TS", sampleEvent.TS)
-			go func() {
-				err := pw.fetchPrices(sampleEvent.Symbols, sampleEvent.TS)
-				if err != nil {
-					pw.logger.Warn("fetch price routine", "error", err.Error())
-					return
-				}
-			}()
+			if pw.streaming && pw.handleStreamOnSampling(sampleEvent.Symbols, time.Now()) {
+				continue
+			}
+			pw.logger.Debug("sampling price", "symbols", sampleEvent.Symbols, "ts", sampleEvent.TS)
+			pw.scheduler.schedule(sampleEvent)
 		}
 	}
 }
 
+// State returns the plugin's statement with the chain ID being checked from the plugin side.
+func (pw *PluginWrapper) State(chainID int64) (types.PluginStatement, error) {
+	return pw.state(chainID)
+}
+
+// FetchPrices fetches the prices of symbols from the plugin directly, the returned samples are not buffered by the
+// wrapper. It is used by the tools which probe a plugin without running the sampling routine of the oracle server, the
+// call is abandoned on the deadline of the plugin's timeout, thus a hanging plugin does not hang the tools.
+func (pw *PluginWrapper) FetchPrices(symbols []string) (types.PluginPriceReport, error) {
+	pw.lockService.Lock()
+	defer pw.lockService.Unlock()
+	return pw.callWithDeadline(func() (types.PluginPriceReport, error) {
+		return pw.adapter.FetchPrices(symbols)
+	})
+}
+
 func (pw *PluginWrapper) state(chainID int64) (types.PluginStatement, error) {
 	var s types.PluginStatement
 	state, err := pw.adapter.State(chainID)
@@ -296,15 +485,26 @@ func (pw *PluginWrapper) state(chainID int64) (types.PluginStatement, error) {
 	return state, nil
 }
 
-func (pw *PluginWrapper) fetchPrices(symbols []string, ts int64) error {
-	// prevent race condition throughout data sampling routines in case of waiting for timeout.
+func (pw *PluginWrapper) fetchPrices(traceCtx trace.SpanContext, symbols []string, ts int64) (err error) {
+	// prevent race condition with the other calls of the plugin service.
 	pw.lockService.Lock()
 	defer pw.lockService.Unlock()
 
-	report, err := pw.adapter.FetchPrices(symbols)
+	// the span starts after the lock is acquired, thus it measures the RPC rather than the waiting on the lock.
+	_, span := tracing.Start(trace.ContextWithSpanContext(context.Background(), traceCtx), "plugin fetch prices",
+		tracing.AttrPlugin.String(pw.name), attribute.Int64("ts", ts), attribute.Int("symbols", len(symbols)))
+	defer func() { tracing.EndWithError(span, err) }()
+
+	start := time.Now()
+	report, err := pw.callWithDeadline(func() (types.PluginPriceReport, error) {
+		return pw.adapter.FetchPrices(symbols)
+	})
+	pw.reliability.ObserveFetch(err, time.Since(start))
 	if err != nil {
 		return err
 	}
+	span.SetAttributes(attribute.Int("prices", len(report.Prices)),
+		attribute.StringSlice("unrecognizable symbols", report.UnRecognizableSymbols))
 
 	if len(report.UnRecognizableSymbols) != 0 {
 		pw.logger.Debug("some symbol are not supported yet in this plugin", "unsupported", report.UnRecognizableSymbols)
@@ -317,10 +517,16 @@ func (pw *PluginWrapper) fetchPrices(symbols []string, ts int64) error {
 			pw.updateMetrics(report.Prices)
 		}
 	}
+
+	if metrics.Enabled && len(report.Metrics) > 0 {
+		pw.forwardMetrics(report.Metrics)
+	}
 	return nil
 }
 
 func (pw *PluginWrapper) updateMetrics(prices []types.Price) {
+	pw.lockMetrics.Lock()
+	defer pw.lockMetrics.Unlock()
 	for _, p := range prices {
 		m, ok := pw.priceMetrics[p.Symbol]
 		if !ok {
@@ -335,11 +541,54 @@ func (pw *PluginWrapper) updateMetrics(prices []types.Price) {
 }
 
 func (pw *PluginWrapper) CleanPluginProcess() {
-	pw.plugin.Kill()
+	pw.kill()
 }
 
 func (pw *PluginWrapper) Close() {
-	pw.plugin.Kill()
+	pw.kill()
 	pw.doneCh <- struct{}{}
 	pw.subSampleEvent.Unsubscribe()
 }
+
+// applyPluginConf writes the plugin configuration into a temp file that is readable by the plugin process only, and
+// passes the path of the file to the plugin process via PluginConfFileEnv. Thus, the configuration, including the
+// service key, is neither exposed to the other plugins nor raced between the concurrent launches. It returns the path
+// of the file to be removed once the plugin is launched.
+func (pw *PluginWrapper) applyPluginConf() (string, error) {
+	conf, err := json.Marshal(pw.conf)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal plugin's configuration: %w", err)
+	}
+
+	// the temp file is created with the permission of 0600.
+	f, err := os.CreateTemp("", "oracle-plugin-*.json")
+	if err != nil {
+		return "", fmt.Errorf("cannot create plugin's configuration file: %w", err)
+	}
+	path := f.Name()
+	if _, err = f.Write(conf); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", fmt.Errorf("cannot write plugin's configuration file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("cannot write plugin's configuration file: %w", err)
+	}
+
+	// the plugin process running as a separate user is granted to read the file.
+	if pw.conf.Limits.UID != 0 {
+		if err = os.Chown(path, pw.conf.Limits.UID, pw.conf.Limits.GID); err != nil {
+			os.Remove(path)
+			return "", fmt.Errorf("cannot grant plugin's configuration file: %w", err)
+		}
+	}
+
+	pw.cmd.Env = append(os.Environ(), fmt.Sprintf("%s=%s", types.PluginConfFileEnv, path))
+	// the legacy configuration in the env of the plugin process is set only on the opt-in of the plugin config, for the
+	// plugins built before the configuration file, as the env is readable from the proc filesystem and it is inherited.
+	if pw.conf.LegacyConfEnv {
+		pw.cmd.Env = append(pw.cmd.Env, fmt.Sprintf("%s=%s", pw.name, conf))
+	}
+	return path, nil
+}
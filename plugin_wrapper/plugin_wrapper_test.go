@@ -1,18 +1,34 @@
 package pluginwrapper
 
 import (
+	"autonity-oracle/config"
+	common2 "autonity-oracle/plugins/common"
 	"autonity-oracle/types"
+	"context"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/hashicorp/go-hclog"
 	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"math/big"
+	"os"
+	"os/exec"
 	"testing"
 	"time"
 )
 
+// TestMain enables the metrics once for all the tests, as the fetchers of the wrappers under test read the flag
+// concurrently.
+func TestMain(m *testing.M) {
+	metrics.Enabled = true
+	os.Exit(m.Run())
+}
+
 func TestPluginWrapper(t *testing.T) {
 	t.Run("test finding nearest data sample", func(t *testing.T) {
 		p := PluginWrapper{
 			logger:           hclog.NewNullLogger(),
+			reliability:      newReliability("test"),
 			samples:          make(map[string]map[int64]types.Price),
 			latestTimestamps: make(map[string]int64),
 			dataSrcType:      types.SrcCEX,
@@ -78,4 +94,484 @@ func TestPluginWrapper(t *testing.T) {
 		p.GCExpiredSamples()
 		require.Equal(t, 1, len(p.samples))
 	})
+	t.Run("test staleness check with symbol metadata", func(t *testing.T) {
+		p := PluginWrapper{
+			logger:           hclog.NewNullLogger(),
+			reliability:      newReliability("test"),
+			samples:          make(map[string]map[int64]types.Price),
+			latestTimestamps: make(map[string]int64),
+			dataSrcType:      types.SrcCEX,
+			symbolMeta: map[string]types.SymbolMetadata{
+				"EUR-USD": {Symbol: "EUR-USD", UpdateInterval: 60, MarketHours: types.MarketWeekdays},
+			},
+		}
+
+		// 2024-01-05 is a Friday.
+		ts := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC).Unix()
+		p.AddSample([]types.Price{
+			{Timestamp: ts, Symbol: "EUR-USD", Price: decimal.RequireFromString("1.1")},
+			{Timestamp: ts, Symbol: "GBP-USD", Price: decimal.RequireFromString("1.2")},
+		}, ts)
+
+		target := ts + int64(staleFactor*60+sampleTTL)
+		_, err := p.AggregatedPrice("EUR-USD", target)
+		require.NoError(t, err)
+
+		_, err = p.AggregatedPrice("EUR-USD", target+1)
+		require.ErrorIs(t, err, types.ErrStalePrice)
+
+		// the symbol without metadata is never stale.
+		_, err = p.AggregatedPrice("GBP-USD", target+1)
+		require.NoError(t, err)
+
+		// the price is not stale on the weekend as the market is closed.
+		_, err = p.AggregatedPrice("EUR-USD", ts+24*3600)
+		require.NoError(t, err)
+	})
+	t.Run("test price stream backpressure and staleness", func(t *testing.T) {
+		streamer := &testStreamer{subscribed: make(chan []string, 1), release: make(chan struct{})}
+		p := PluginWrapper{
+			logger:    hclog.NewNullLogger(),
+			adapter:   streamer,
+			streaming: true,
+		}
+		symbols := []string{"NTN-USD", "ATN-USD"}
+		now := time.Now()
+		subscribed := func() {
+			require.Equal(t, symbols, <-streamer.subscribed)
+			require.Eventually(t, func() bool { return !p.subscribing.Load() }, time.Second, time.Millisecond)
+		}
+
+		// the first sampling subscribes the stream without waiting for the RPC, and it polls until the stream pushes.
+		require.False(t, p.handleStreamOnSampling(symbols, now))
+		// the in-flight subscription is not issued twice.
+		require.False(t, p.handleStreamOnSampling(symbols, now.Add(2*streamStaleTimeout)))
+		close(streamer.release)
+		subscribed()
+		require.Empty(t, streamer.subscribed)
+
+		for i := 0; i < streamBufferSize; i++ {
+			require.NoError(t, p.stream.PushPrices([]types.Price{{Symbol: "NTN-USD"}}))
+		}
+		require.ErrorIs(t, p.stream.PushPrices([]types.Price{{Symbol: "NTN-USD"}}), types.ErrStreamBackpressure)
+		require.Equal(t, streamBufferSize, len(p.streamCh()))
+
+		// the fresh stream covers the sampling, regardless of the order of the symbols.
+		require.True(t, p.handleStreamOnSampling([]string{"ATN-USD", "NTN-USD"}, now))
+
+		// the heartbeat keeps the stream fresh without buffering prices.
+		<-p.streamCh()
+		require.NoError(t, p.stream.PushPrices(nil))
+		require.Equal(t, streamBufferSize-1, len(p.streamCh()))
+
+		// the stale stream falls back to polling, and it is re-subscribed.
+		later := time.Now().Add(2 * streamStaleTimeout)
+		require.False(t, p.handleStreamOnSampling(symbols, later))
+		subscribed()
+
+		// a change of the symbols re-subscribes the stream.
+		symbols = []string{"NTN-USD"}
+		require.False(t, p.handleStreamOnSampling(symbols, later))
+		subscribed()
+	})
+
+	t.Run("test fetch scheduler coalesces and drops sample events", func(t *testing.T) {
+		s := newFetchScheduler("test")
+		s.schedule(&types.SampleEvent{Symbols: []string{"NTN-USD"}, TS: 1})
+		// the routine is busy, the events are coalesced into the pending one.
+		s.schedule(&types.SampleEvent{Symbols: []string{"ATN-USD"}, TS: 2})
+		s.schedule(&types.SampleEvent{Symbols: []string{"ATN-USD"}, TS: 3})
+		// the out of order event is dropped.
+		s.schedule(&types.SampleEvent{Symbols: []string{"EUR-USD"}, TS: 2})
+		require.Equal(t, int64(2), s.coalesced)
+		require.Equal(t, int64(1), s.dropped)
+
+		e := s.next()
+		require.Equal(t, int64(3), e.TS)
+		require.ElementsMatch(t, []string{"NTN-USD", "ATN-USD"}, e.Symbols)
+		require.Nil(t, s.next())
+	})
+
+	t.Run("test backfill the samples missed the target", func(t *testing.T) {
+		adapter := &historicalAdapter{}
+		p := PluginWrapper{
+			logger:           hclog.NewNullLogger(),
+			adapter:          adapter,
+			historical:       true,
+			dataSrcType:      types.SrcCEX,
+			samples:          make(map[string]map[int64]types.Price),
+			latestTimestamps: make(map[string]int64),
+			reliability:      newReliability("test"),
+		}
+		target := int64(1700000000)
+		p.AddSample([]types.Price{{Symbol: "EUR-USD", Timestamp: target - 1, Price: decimal.RequireFromString("1.0")}}, target-1)
+		p.AddSample([]types.Price{{Symbol: "GBP-USD", Timestamp: target - 20, Price: decimal.RequireFromString("1.2")}}, target-20)
+
+		require.NoError(t, p.BackfillTarget(context.Background(), []string{"EUR-USD", "GBP-USD", "JPY-USD"}, target))
+		require.Equal(t, []string{"GBP-USD", "JPY-USD"}, adapter.asked)
+
+		price, err := p.AggregatedPrice("GBP-USD", target)
+		require.NoError(t, err)
+		require.Equal(t, "1.1", price.Price.String())
+		require.Equal(t, target, price.Timestamp)
+	})
+
+	t.Run("test backfill is bounded by the context", func(t *testing.T) {
+		adapter := &historicalAdapter{release: make(chan struct{})}
+		p := PluginWrapper{
+			logger:           hclog.NewNullLogger(),
+			adapter:          adapter,
+			historical:       true,
+			dataSrcType:      types.SrcCEX,
+			samples:          make(map[string]map[int64]types.Price),
+			latestTimestamps: make(map[string]int64),
+			reliability:      newReliability("test"),
+		}
+		target := int64(1700000000)
+		symbols := []string{"EUR-USD"}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.ErrorIs(t, p.BackfillTarget(ctx, symbols, target), context.DeadlineExceeded)
+
+		// the abandoned backfill does not block the fetches, but the next backfill is skipped until it returns.
+		require.Nil(t, p.abandoned)
+		require.ErrorIs(t, p.BackfillTarget(context.Background(), symbols, target), types.ErrPluginBusy)
+		close(adapter.release)
+		require.Eventually(t, func() bool { return !p.backfilling.Load() }, time.Second, time.Millisecond)
+		require.NoError(t, p.BackfillTarget(context.Background(), symbols, target))
+
+		// the plugin with a pending abandoned fetch is skipped.
+		pending := make(chan fetchResult, 1)
+		p.abandoned = pending
+		require.ErrorIs(t, p.BackfillTarget(context.Background(), []string{"GBP-USD"}, target), types.ErrPluginBusy)
+		pending <- fetchResult{}
+		require.NoError(t, p.BackfillTarget(context.Background(), []string{"GBP-USD"}, target))
+		require.Nil(t, p.abandoned)
+	})
+
+	t.Run("test samples snapshot is reloaded with TTL filtering", func(t *testing.T) {
+		newWrapper := func() *PluginWrapper {
+			return &PluginWrapper{
+				name:             "test",
+				logger:           hclog.NewNullLogger(),
+				dataSrcType:      types.SrcAMM,
+				samples:          make(map[string]map[int64]types.Price),
+				latestTimestamps: make(map[string]int64),
+				reliability:      newReliability("test"),
+			}
+		}
+
+		dir := t.TempDir()
+		now := time.Now().Unix()
+		p := newWrapper()
+		p.AddSample([]types.Price{{Symbol: "NTN-USDC", Timestamp: now - 2, Price: decimal.RequireFromString("1.1"), Volume: big.NewInt(10)}}, now-2)
+		p.AddSample([]types.Price{{Symbol: "NTN-USDC", Timestamp: now - 1, Price: decimal.RequireFromString("1.3"), Volume: big.NewInt(10)}}, now-1)
+		p.AddSample([]types.Price{{Symbol: "ATN-USDC", Timestamp: now - 100, Price: decimal.RequireFromString("2.0"), Volume: big.NewInt(10)}}, now-100)
+		require.NoError(t, p.SaveSamples(dir))
+
+		restarted := newWrapper()
+		loaded, err := restarted.LoadSamples(dir)
+		require.NoError(t, err)
+		require.Equal(t, 2, loaded)
+
+		price, err := restarted.AggregatedPrice("NTN-USDC", now)
+		require.NoError(t, err)
+		require.Equal(t, "1.2", price.Price.String())
+		_, err = restarted.AggregatedPrice("ATN-USDC", now)
+		require.ErrorIs(t, err, types.ErrNoAvailablePrice)
+
+		// a missing snapshot is not an error.
+		loaded, err = newWrapper().LoadSamples(t.TempDir())
+		require.NoError(t, err)
+		require.Equal(t, 0, loaded)
+	})
+
+	t.Run("test fetch deadline", func(t *testing.T) {
+		defaultTimeout := defaultFetchTimeout
+		defaultFetchTimeout = 50 * time.Millisecond
+		defer func() { defaultFetchTimeout = defaultTimeout }()
+
+		adapter := &slowAdapter{release: make(chan struct{}), returned: make(chan struct{}, 1)}
+		p := PluginWrapper{
+			logger:  hclog.NewNullLogger(),
+			adapter: adapter,
+		}
+
+		_, err := p.callWithDeadline(func() (types.PluginPriceReport, error) {
+			return adapter.FetchPrices([]string{"NTN-USD"})
+		})
+		require.ErrorIs(t, err, types.ErrFetchTimeout)
+		// no further call is issued until the abandoned one returns.
+		_, err = p.callWithDeadline(func() (types.PluginPriceReport, error) {
+			return adapter.FetchPrices([]string{"NTN-USD"})
+		})
+		require.ErrorIs(t, err, types.ErrPluginBusy)
+
+		close(adapter.release)
+		<-adapter.returned
+		require.Eventually(t, func() bool {
+			_, err = p.callWithDeadline(func() (types.PluginPriceReport, error) {
+				return adapter.FetchPrices([]string{"NTN-USD"})
+			})
+			return err == nil
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("test direct fetch deadline", func(t *testing.T) {
+		defaultTimeout := defaultFetchTimeout
+		defaultFetchTimeout = 50 * time.Millisecond
+		defer func() { defaultFetchTimeout = defaultTimeout }()
+
+		adapter := &slowAdapter{release: make(chan struct{}), returned: make(chan struct{}, 1)}
+		p := PluginWrapper{
+			logger:  hclog.NewNullLogger(),
+			adapter: adapter,
+		}
+		defer close(adapter.release)
+
+		// the tools fetching the prices directly are not hung by a hanging plugin.
+		_, err := p.FetchPrices([]string{"NTN-USD"})
+		require.ErrorIs(t, err, types.ErrFetchTimeout)
+		_, err = p.FetchPrices([]string{"NTN-USD"})
+		require.ErrorIs(t, err, types.ErrPluginBusy)
+	})
+}
+
+type testSubscriber struct {
+	feed event.Feed
+}
+
+func (s *testSubscriber) WatchSampleEvent(sink chan<- *types.SampleEvent) event.Subscription {
+	return s.feed.Subscribe(sink)
+}
+
+type testClient struct {
+	closed bool
+}
+
+func (c *testClient) AvailableSymbols() ([]string, error) {
+	return []string{"NTN-USD", "ATN-USD"}, nil
+}
+
+func (c *testClient) FetchPrice(symbols []string) (common2.Prices, error) {
+	var prices common2.Prices
+	for _, s := range symbols {
+		prices = append(prices, common2.Price{Symbol: s, Price: "1.5", Volume: "1"})
+	}
+	return prices, nil
+}
+
+func (c *testClient) KeyRequired() bool {
+	return false
+}
+
+func (c *testClient) Close() {
+	c.closed = true
+}
+
+func TestInProcessPlugin(t *testing.T) {
+	client := &testClient{}
+	common2.RegisterAdapter("inprocess_test", &config.PluginConfig{Name: "inprocess_test", DataUpdateInterval: 1},
+		func(conf *config.PluginConfig) (*common2.Plugin, error) {
+			return common2.NewPlugin(conf, client, "v0.0.1", types.SrcCEX, nil), nil
+		})
+
+	sub := &testSubscriber{}
+	pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "inprocess_test", sub, &config.PluginConfig{Name: "inprocess_test"})
+	require.NoError(t, pw.Initialize(0))
+	require.True(t, pw.InProcess())
+	require.False(t, pw.Exited())
+	require.Equal(t, "", pw.CheckLimits())
+	require.Equal(t, "v0.0.1", pw.Version())
+	require.Equal(t, types.ProtocolVersionSymbolMetadata, pw.ProtocolVersion())
+
+	ts := time.Now().Unix()
+	require.Eventually(t, func() bool {
+		return sub.feed.Send(&types.SampleEvent{Symbols: []string{"NTN-USD", "EUR-USD"}, TS: ts}) > 0
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		price, err := pw.AggregatedPrice("NTN-USD", ts)
+		return err == nil && price.Price.String() == "1.5"
+	}, time.Second, 10*time.Millisecond)
+
+	pw.Close()
+	require.True(t, client.closed)
+}
+
+type testStreamClient struct {
+	testClient
+	updates chan struct{}
+	metrics *common2.Metrics
+}
+
+func (c *testStreamClient) SetMetrics(m *common2.Metrics) {
+	c.metrics = m
+}
+
+func (c *testStreamClient) PriceUpdates() <-chan struct{} {
+	return c.updates
+}
+
+func TestInProcessStreaming(t *testing.T) {
+	client := &testStreamClient{updates: make(chan struct{})}
+	common2.RegisterAdapter("stream_test", &config.PluginConfig{Name: "stream_test", DataUpdateInterval: 1},
+		func(conf *config.PluginConfig) (*common2.Plugin, error) {
+			return common2.NewPlugin(conf, client, "v0.0.1", types.SrcCEX, nil), nil
+		})
+
+	sub := &testSubscriber{}
+	pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "stream_test", sub, &config.PluginConfig{Name: "stream_test"})
+	require.NoError(t, pw.Initialize(0))
+	defer pw.Close()
+	require.True(t, pw.streaming)
+
+	// the first sampling subscribes the stream, then the pushed prices are sampled and observed as fetches.
+	ts := time.Now().Unix()
+	require.Eventually(t, func() bool {
+		return sub.feed.Send(&types.SampleEvent{Symbols: []string{"NTN-USD"}, TS: ts}) > 0
+	}, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		select {
+		case client.updates <- struct{}{}:
+		default:
+		}
+		pw.reliability.lock.RLock()
+		defer pw.reliability.lock.RUnlock()
+		return pw.reliability.observations >= 3
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestPollMetrics(t *testing.T) {
+	// the streaming plugin is not polled for prices, thus its metrics are exported by the poll only.
+	client := &testStreamClient{updates: make(chan struct{})}
+	common2.RegisterAdapter("poll_metrics_test", &config.PluginConfig{Name: "poll_metrics_test", DataUpdateInterval: 1},
+		func(conf *config.PluginConfig) (*common2.Plugin, error) {
+			return common2.NewPlugin(conf, client, "v0.0.1", types.SrcAMM, nil), nil
+		})
+
+	pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "poll_metrics_test", &testSubscriber{},
+		&config.PluginConfig{Name: "poll_metrics_test"})
+	require.NoError(t, pw.Initialize(0))
+	defer pw.Close()
+	require.True(t, pw.streaming)
+	require.True(t, pw.reportsMetrics)
+
+	client.metrics.Inc("swap_events", 2)
+	pw.PollMetrics()
+	require.Eventually(t, func() bool {
+		counter, ok := metrics.DefaultRegistry.Get("oracle/poll_metrics_test/plugin/swap_events").(metrics.Counter)
+		return ok && counter.Count() == 2
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestConfigSchema(t *testing.T) {
+	defConf := &config.PluginConfig{Name: "schema_test", Scheme: "https", Endpoint: "example.com"}
+	common2.RegisterAdapter("schema_test", defConf, func(conf *config.PluginConfig) (*common2.Plugin, error) {
+		p := common2.NewPlugin(conf, &testClient{}, "v0.0.1", types.SrcCEX, nil)
+		p.PublishConfigSchema(defConf, types.ConfigField{Name: "swapAddress", Type: types.ConfigAddress, Required: true})
+		return p, nil
+	})
+
+	t.Run("test invalid config is rejected", func(t *testing.T) {
+		pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "schema_test", &testSubscriber{},
+			&config.PluginConfig{Name: "schema_test", SwapAddress: "0x1234"})
+		err := pw.Initialize(0)
+		require.ErrorIs(t, err, types.ErrInvalidPluginConfig)
+		pw.CleanPluginProcess()
+	})
+
+	t.Run("test valid config", func(t *testing.T) {
+		pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "schema_test", &testSubscriber{},
+			&config.PluginConfig{Name: "schema_test", SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88"})
+		require.NoError(t, pw.Initialize(0))
+		defer pw.Close()
+		require.Equal(t, 7, len(pw.ConfigSchema()))
+	})
+}
+
+func TestForwardMetrics(t *testing.T) {
+	pw := NewInProcessPluginWrapper(hclog.NewNullLogger(), "metrics_test", &testSubscriber{}, &config.PluginConfig{Name: "metrics_test"})
+	pw.forwardMetrics([]types.Metric{
+		{Name: "http/status/429", Type: types.MetricCounter, Value: 3},
+		{Name: "cache/hit_ratio", Type: types.MetricGauge, Value: 0.5},
+		{Name: "invalid name", Type: types.MetricCounter, Value: 1},
+	})
+	counter := metrics.DefaultRegistry.Get("oracle/metrics_test/plugin/http/status/429").(metrics.Counter)
+	require.Equal(t, int64(3), counter.Count())
+	gauge := metrics.DefaultRegistry.Get("oracle/metrics_test/plugin/cache/hit_ratio").(metrics.GaugeFloat64)
+	require.Equal(t, 0.5, gauge.Value())
+	require.Nil(t, metrics.DefaultRegistry.Get("oracle/metrics_test/plugin/invalid name"))
+
+	// the cumulative counts are taken as increments.
+	pw.forwardMetrics([]types.Metric{{Name: "http/status/429", Type: types.MetricCounter, Value: 5}})
+	require.Equal(t, int64(5), counter.Count())
+
+	// the count of a restarted plugin process starts from zero.
+	pw.forwardMetrics([]types.Metric{{Name: "http/status/429", Type: types.MetricCounter, Value: 2}})
+	require.Equal(t, int64(7), counter.Count())
+}
+
+type historicalAdapter struct {
+	types.Adapter
+	asked   []string
+	release chan struct{} // the call hangs until it is released, if it is set.
+}
+
+func (a *historicalAdapter) FetchPriceAt(symbols []string, ts int64) (types.PluginPriceReport, error) {
+	if a.release != nil {
+		<-a.release
+	}
+	a.asked = symbols
+	var report types.PluginPriceReport
+	for _, s := range symbols {
+		report.Prices = append(report.Prices, types.Price{Symbol: s, Timestamp: ts, Price: decimal.RequireFromString("1.1")})
+	}
+	return report, nil
+}
+
+type slowAdapter struct {
+	types.Adapter
+	release  chan struct{}
+	returned chan struct{}
+}
+
+func (a *slowAdapter) FetchPrices(_ []string) (types.PluginPriceReport, error) {
+	<-a.release
+	select {
+	case a.returned <- struct{}{}:
+	default:
+	}
+	return types.PluginPriceReport{}, nil
+}
+
+type testStreamer struct {
+	types.Adapter
+	subscribed chan []string
+	release    chan struct{}
+}
+
+func (s *testStreamer) StreamPrices(symbols []string, _ types.PriceSink) error {
+	<-s.release
+	s.subscribed <- symbols
+	return nil
+}
+
+func TestApplyPluginConf(t *testing.T) {
+	pw := &PluginWrapper{name: "forex_test", conf: &config.PluginConfig{Name: "forex_test", Key: "secret"},
+		cmd: exec.Command("forex_test")}
+	path, err := pw.applyPluginConf()
+	require.NoError(t, err)
+	defer os.Remove(path)
+	require.Contains(t, pw.cmd.Env, types.PluginConfFileEnv+"="+path)
+	for _, env := range pw.cmd.Env {
+		require.NotContains(t, env, "secret")
+	}
+
+	// the config is set in the env of the plugin process only on the legacy opt-in.
+	pw.conf.LegacyConfEnv = true
+	path, err = pw.applyPluginConf()
+	require.NoError(t, err)
+	defer os.Remove(path)
+	require.Contains(t, pw.cmd.Env[len(pw.cmd.Env)-1], `"key":"secret"`)
 }
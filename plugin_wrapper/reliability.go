@@ -0,0 +1,138 @@
+package pluginwrapper
+
+import (
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/shopspring/decimal"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	reliabilityAlpha   = 0.1             // the weight of the latest observation in the rolling statistics.
+	minObservations    = 10              // the number of fetches before the score takes effect.
+	deviationTolerance = 0.01            // the relative deviation tolerated without down-weighting, 1%.
+	latencyTolerance   = 2 * time.Second // the fetching latency tolerated without down-weighting.
+	exclusionThreshold = 0.2             // a plugin scored under the threshold is excluded from the aggregation.
+	reliabilityMetrics = []string{"score", "success", "latency", "stale", "deviation", "median_deviation"}
+)
+
+// Reliability keeps the rolling statistics of a plugin with exponential moving averages, they are scored to weight
+// the prices of the plugin in the aggregation.
+type Reliability struct {
+	lock            sync.RWMutex
+	observations    int
+	successRate     float64       // the rate of the successful FetchPrices calls.
+	latency         time.Duration // the latency of the FetchPrices calls.
+	staleRate       float64       // the rate of the stale samples on aggregation.
+	deviation       float64       // the relative deviation of the plugin's prices from the final aggregated prices.
+	medianDeviation float64       // the relative deviation of the plugin's prices from the on-chain medians.
+
+	gauges map[string]metrics.GaugeFloat64
+}
+
+func newReliability(name string) *Reliability {
+	r := &Reliability{
+		successRate: 1,
+		gauges:      make(map[string]metrics.GaugeFloat64),
+	}
+	for _, m := range reliabilityMetrics {
+		r.gauges[m] = metrics.GetOrRegisterGaugeFloat64(strings.Join([]string{"oracle", name, "reliability", m}, "/"), nil)
+	}
+	return r
+}
+
+func ewma(avg float64, sample float64) float64 {
+	return avg + reliabilityAlpha*(sample-avg)
+}
+
+// relativeDeviation returns |price - ref| / ref, it returns 0 if the reference is zero.
+func relativeDeviation(price, ref decimal.Decimal) float64 {
+	if ref.IsZero() {
+		return 0
+	}
+	return price.Sub(ref).Abs().Div(ref.Abs()).InexactFloat64()
+}
+
+// ObserveFetch records the result and the latency of a FetchPrices call.
+func (r *Reliability) ObserveFetch(err error, latency time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	success := 1.0
+	if err != nil {
+		success = 0
+	}
+	r.observations++
+	r.successRate = ewma(r.successRate, success)
+	r.latency = time.Duration(ewma(float64(r.latency), float64(latency)))
+}
+
+// ObserveStaleness records if a sample is stale on aggregation.
+func (r *Reliability) ObserveStaleness(stale bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	sample := 0.0
+	if stale {
+		sample = 1
+	}
+	r.staleRate = ewma(r.staleRate, sample)
+}
+
+// ObserveDeviation records the deviation of the plugin's price from the final aggregated price.
+func (r *Reliability) ObserveDeviation(price, aggregated decimal.Decimal) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.deviation = ewma(r.deviation, relativeDeviation(price, aggregated))
+}
+
+// ObserveMedianDeviation records the deviation of the plugin's price from the on-chain median.
+func (r *Reliability) ObserveMedianDeviation(price, median decimal.Decimal) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.medianDeviation = ewma(r.medianDeviation, relativeDeviation(price, median))
+}
+
+// Score returns the reliability of the plugin in the range of [0, 1], the plugin is fully trusted until there are
+// enough observations. The deviation and the latency beyond their tolerance are penalized in inverse proportion.
+func (r *Reliability) Score() float64 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.score()
+}
+
+func (r *Reliability) score() float64 {
+	if r.observations < minObservations {
+		return 1
+	}
+
+	score := r.successRate * (1 - r.staleRate)
+	deviation := r.deviation
+	if r.medianDeviation > deviation {
+		deviation = r.medianDeviation
+	}
+	if deviation > deviationTolerance {
+		score *= deviationTolerance / deviation
+	}
+	if r.latency > latencyTolerance {
+		score *= float64(latencyTolerance) / float64(r.latency)
+	}
+	return score
+}
+
+// Excluded returns true if the plugin is scored under the exclusionThreshold, the exclusion is temporary as the
+// statistics keep rolling.
+func (r *Reliability) Excluded() bool {
+	return r.Score() < exclusionThreshold
+}
+
+// UpdateMetrics reports the statistics and the score as metrics.
+func (r *Reliability) UpdateMetrics() {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	r.gauges["score"].Update(r.score())
+	r.gauges["success"].Update(r.successRate)
+	r.gauges["latency"].Update(float64(r.latency.Milliseconds()))
+	r.gauges["stale"].Update(r.staleRate)
+	r.gauges["deviation"].Update(r.deviation)
+	r.gauges["median_deviation"].Update(r.medianDeviation)
+}
@@ -0,0 +1,48 @@
+package pluginwrapper
+
+import (
+	"errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestReliability(t *testing.T) {
+	t.Run("test full score before enough observations", func(t *testing.T) {
+		r := newReliability("test_reliability_warmup")
+		for i := 0; i < minObservations-1; i++ {
+			r.ObserveFetch(errors.New("fetch failure"), time.Second)
+		}
+		require.Equal(t, float64(1), r.Score())
+		require.False(t, r.Excluded())
+	})
+
+	t.Run("test deviation down-weights the score", func(t *testing.T) {
+		r := newReliability("test_reliability_deviation")
+		for i := 0; i < minObservations; i++ {
+			r.ObserveFetch(nil, time.Millisecond)
+		}
+		require.Equal(t, float64(1), r.Score())
+
+		for i := 0; i < minObservations; i++ {
+			r.ObserveDeviation(decimal.RequireFromString("1.02"), decimal.RequireFromString("1.0"))
+		}
+		score := r.Score()
+		require.Less(t, score, float64(1))
+		require.Greater(t, score, exclusionThreshold)
+	})
+
+	t.Run("test exclusion on failures and recovery", func(t *testing.T) {
+		r := newReliability("test_reliability_exclusion")
+		for i := 0; i < 3*minObservations; i++ {
+			r.ObserveFetch(errors.New("fetch failure"), time.Millisecond)
+		}
+		require.True(t, r.Excluded())
+
+		for i := 0; i < 3*minObservations; i++ {
+			r.ObserveFetch(nil, time.Millisecond)
+		}
+		require.False(t, r.Excluded())
+	})
+}
@@ -0,0 +1,153 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"github.com/ethereum/go-ethereum/metrics"
+	"strings"
+	"sync"
+	"time"
+)
+
+var defaultFetchTimeout = 10 * time.Second // the deadline of a fetch if the plugin's timeout is not configured.
+
+// fetchScheduler schedules the sample events of a plugin to a single fetching routine, thus there is at most one
+// in-flight fetch per plugin. The events arriving while the routine is busy are coalesced into one pending event, which
+// carries the latest timestamp and the union of the symbols, while the events older than the latest one are dropped.
+type fetchScheduler struct {
+	lock     sync.Mutex
+	pending  *types.SampleEvent
+	latestTS int64 // the timestamp of the latest event being scheduled.
+	wake     chan struct{}
+	quit     chan struct{}
+
+	coalesced        int64
+	dropped          int64
+	coalescedCounter metrics.Counter
+	droppedCounter   metrics.Counter
+}
+
+func newFetchScheduler(name string) *fetchScheduler {
+	return &fetchScheduler{
+		wake:             make(chan struct{}, 1),
+		quit:             make(chan struct{}),
+		coalescedCounter: metrics.GetOrRegisterCounter(strings.Join([]string{"oracle", name, "sample_events", "coalesced"}, "/"), nil),
+		droppedCounter:   metrics.GetOrRegisterCounter(strings.Join([]string{"oracle", name, "sample_events", "dropped"}, "/"), nil),
+	}
+}
+
+// schedule queues the sample event for the fetching routine, it never blocks.
+func (s *fetchScheduler) schedule(e *types.SampleEvent) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if e.TS <= s.latestTS {
+		s.dropped++
+		if metrics.Enabled {
+			s.droppedCounter.Inc(1)
+		}
+		return
+	}
+	s.latestTS = e.TS
+
+	if s.pending != nil {
+		e = mergeSampleEvents(s.pending, e)
+		s.coalesced++
+		if metrics.Enabled {
+			s.coalescedCounter.Inc(1)
+		}
+	}
+	s.pending = e
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next takes the pending event, it returns nil if there is none.
+func (s *fetchScheduler) next() *types.SampleEvent {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	e := s.pending
+	s.pending = nil
+	return e
+}
+
+func (s *fetchScheduler) stop() {
+	close(s.quit)
+}
+
+// mergeSampleEvents merges the pending event into the newer one, the symbols of both are sampled at the newer timestamp.
+func mergeSampleEvents(pending, newer *types.SampleEvent) *types.SampleEvent {
+	symbols := make([]string, len(newer.Symbols), len(newer.Symbols)+len(pending.Symbols))
+	copy(symbols, newer.Symbols)
+	seen := make(map[string]struct{}, len(symbols))
+	for _, s := range symbols {
+		seen[s] = struct{}{}
+	}
+	for _, s := range pending.Symbols {
+		if _, ok := seen[s]; !ok {
+			symbols = append(symbols, s)
+		}
+	}
+	return &types.SampleEvent{Symbols: symbols, TS: newer.TS, TraceCtx: newer.TraceCtx}
+}
+
+// runFetcher is the fetching routine of the plugin, it fetches the prices of the scheduled events one by one.
+func (pw *PluginWrapper) runFetcher() {
+	for {
+		select {
+		case <-pw.scheduler.quit:
+			return
+		case <-pw.scheduler.wake:
+		}
+
+		for e := pw.scheduler.next(); e != nil; e = pw.scheduler.next() {
+			if err := pw.fetchPrices(e.TraceCtx, e.Symbols, e.TS); err != nil {
+				pw.logger.Warn("fetch price routine", "error", err.Error())
+			}
+		}
+	}
+}
+
+type fetchResult struct {
+	report types.PluginPriceReport
+	err    error
+}
+
+// callWithDeadline calls the plugin with the deadline of the plugin's timeout. As the RPC cannot be cancelled, the call
+// is abandoned on the deadline and its late result is discarded, and no further call is issued until the abandoned one
+// returns. It is called with the lockService held.
+func (pw *PluginWrapper) callWithDeadline(call func() (types.PluginPriceReport, error)) (types.PluginPriceReport, error) {
+	if pw.abandoned != nil {
+		select {
+		case <-pw.abandoned:
+			pw.abandoned = nil
+		default:
+			return types.PluginPriceReport{}, types.ErrPluginBusy
+		}
+	}
+
+	done := make(chan fetchResult, 1)
+	go func() {
+		report, err := call()
+		done <- fetchResult{report: report, err: err}
+	}()
+
+	timer := time.NewTimer(pw.fetchTimeout())
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.report, r.err
+	case <-timer.C:
+		pw.abandoned = done
+		return types.PluginPriceReport{}, types.ErrFetchTimeout
+	}
+}
+
+func (pw *PluginWrapper) fetchTimeout() time.Duration {
+	if pw.conf == nil || pw.conf.Timeout <= 0 {
+		return defaultFetchTimeout
+	}
+	return time.Duration(pw.conf.Timeout) * time.Second
+}
@@ -0,0 +1,106 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const snapshotFileSuffix = ".samples.json"
+
+// sampleRecord is a sample in the snapshot, the sampling timestamp is kept as it can differ from the price timestamp.
+type sampleRecord struct {
+	TS    int64       `json:"ts"`
+	Price types.Price `json:"price"`
+}
+
+// samplesSnapshot is the schema of the snapshot file of a plugin's samples.
+type samplesSnapshot struct {
+	Plugin  string                    `json:"plugin"`
+	SavedAt int64                     `json:"savedAt"`
+	Samples map[string][]sampleRecord `json:"samples"`
+}
+
+func snapshotFile(dir, name string) string {
+	return filepath.Join(dir, name+snapshotFileSuffix)
+}
+
+// SaveSamples writes the buffered samples into the snapshot file of the plugin in the directory, the file is replaced
+// atomically, thus a crash during the writing does not leave a broken snapshot.
+func (pw *PluginWrapper) SaveSamples(dir string) error {
+	snapshot := samplesSnapshot{
+		Plugin:  pw.name,
+		SavedAt: time.Now().Unix(),
+		Samples: make(map[string][]sampleRecord),
+	}
+
+	pw.lockSamples.RLock()
+	for symbol, tsMap := range pw.samples {
+		for ts, p := range tsMap {
+			snapshot.Samples[symbol] = append(snapshot.Samples[symbol], sampleRecord{TS: ts, Price: p})
+		}
+	}
+	pw.lockSamples.RUnlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("cannot marshal samples snapshot: %w", err)
+	}
+
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("cannot create snapshot directory: %w", err)
+	}
+
+	f, err := os.CreateTemp(dir, pw.name+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create samples snapshot: %w", err)
+	}
+	tmp := f.Name()
+	if _, err = f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("cannot write samples snapshot: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("cannot write samples snapshot: %w", err)
+	}
+	if err = os.Rename(tmp, snapshotFile(dir, pw.name)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("cannot replace samples snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSamples reloads the samples from the snapshot file of the plugin in the directory, the samples older than the
+// sampleTTL are dropped. It returns the number of the reloaded samples, a missing snapshot is not an error.
+func (pw *PluginWrapper) LoadSamples(dir string) (int, error) {
+	data, err := os.ReadFile(snapshotFile(dir, pw.name))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("cannot read samples snapshot: %w", err)
+	}
+
+	var snapshot samplesSnapshot
+	if err = json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("cannot decode samples snapshot: %w", err)
+	}
+
+	threshold := time.Now().Unix() - int64(sampleTTL)
+	loaded := 0
+	for symbol, records := range snapshot.Samples {
+		for _, r := range records {
+			if r.TS < threshold || r.Price.Symbol != symbol {
+				continue
+			}
+			pw.AddSample([]types.Price{r.Price}, r.TS)
+			loaded++
+		}
+	}
+	return loaded, nil
+}
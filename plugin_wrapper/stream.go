@@ -0,0 +1,138 @@
+package pluginwrapper
+
+import (
+	"autonity-oracle/types"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	streamBufferSize   = 16               // the number of pushed batches buffered before the backpressure.
+	streamStaleTimeout = 10 * time.Second // shorter than the sample TTL, thus the fallback polling fills the gap.
+)
+
+// priceStream is the sink of the prices pushed by a streaming plugin, the prices are buffered in a bounded channel
+// which is drained by the main loop of the wrapper, a push is rejected with backpressure once the buffer is full.
+type priceStream struct {
+	ch           chan []types.Price
+	symbols      string // the sorted, joined symbols that are subscribed.
+	subscribedAt time.Time
+	lastPushAt   atomic.Int64 // unix nano of the last push, it is written by the plugin's RPC routine.
+}
+
+func newPriceStream(symbols []string) *priceStream {
+	return &priceStream{
+		ch:           make(chan []types.Price, streamBufferSize),
+		symbols:      symbolsKey(symbols),
+		subscribedAt: time.Now(),
+	}
+}
+
+// PushPrices buffers the pushed prices, a push without prices is the heartbeat of a quiet stream, it keeps the stream
+// fresh without being buffered.
+func (s *priceStream) PushPrices(prices []types.Price) error {
+	if len(prices) == 0 {
+		s.lastPushAt.Store(time.Now().UnixNano())
+		return nil
+	}
+
+	select {
+	case s.ch <- prices:
+		s.lastPushAt.Store(time.Now().UnixNano())
+		return nil
+	default:
+		return types.ErrStreamBackpressure
+	}
+}
+
+// fresh returns true if the stream has pushed prices within the timeout.
+func (s *priceStream) fresh(now time.Time) bool {
+	ns := s.lastPushAt.Load()
+	return ns != 0 && now.Sub(time.Unix(0, ns)) <= streamStaleTimeout
+}
+
+func symbolsKey(symbols []string) string {
+	sorted := append([]string(nil), symbols...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// subscribe asks the plugin to stream the prices of the symbols, it replaces the previous stream of the plugin.
+func (pw *PluginWrapper) subscribe(symbols []string) {
+	streamer, ok := pw.adapter.(types.PriceStreamer)
+	if !ok {
+		return
+	}
+
+	// the subscription RPC is issued from a routine, thus the main loop keeps draining the sample events, and it is
+	// skipped if the previous subscription is still in-flight.
+	if !pw.subscribing.CompareAndSwap(false, true) {
+		return
+	}
+
+	// keep the failed stream as well, it is stale after the timeout and then the subscription is retried.
+	stream := newPriceStream(symbols)
+	pw.stream = stream
+	go func() {
+		defer pw.subscribing.Store(false)
+		pw.lockService.Lock()
+		err := streamer.StreamPrices(symbols, stream)
+		pw.lockService.Unlock()
+		if err != nil {
+			pw.reliability.ObserveFetch(err, 0)
+			pw.logger.Warn("cannot subscribe price stream", "error", err.Error())
+			return
+		}
+		pw.logger.Debug("subscribed price stream", "symbols", symbols)
+	}()
+}
+
+// streamFresh returns true if the stream of the symbols is pushing prices, thus polling is not required.
+func (pw *PluginWrapper) streamFresh(symbols []string, now time.Time) bool {
+	if pw.stream == nil || pw.stream.symbols != symbolsKey(symbols) {
+		return false
+	}
+	return pw.stream.fresh(now)
+}
+
+// handleStreamOnSampling keeps the stream subscribed to the sampling symbols, it returns true if the sampling is
+// covered by the stream, otherwise the wrapper falls back to poll the plugin.
+func (pw *PluginWrapper) handleStreamOnSampling(symbols []string, now time.Time) bool {
+	if pw.streamFresh(symbols, now) {
+		return true
+	}
+
+	switch {
+	case pw.stream == nil || pw.stream.symbols != symbolsKey(symbols):
+		pw.subscribe(symbols)
+	// give the subscription a timeout to push, before re-subscribing it.
+	case now.Sub(pw.stream.subscribedAt) > streamStaleTimeout:
+		pw.logger.Warn("price stream is stale, fall back to polling and re-subscribe", "symbols", symbols)
+		pw.subscribe(symbols)
+	}
+	return false
+}
+
+// streamCh returns the channel of the pushed prices, a nil channel blocks forever if the plugin is not streaming.
+func (pw *PluginWrapper) streamCh() chan []types.Price {
+	if pw.stream == nil {
+		return nil
+	}
+	return pw.stream.ch
+}
+
+// pushLatency is the latency of a push, it is the age of the latest price in the push, as the stream is not requested.
+func pushLatency(prices []types.Price, now time.Time) time.Duration {
+	var latest int64
+	for _, p := range prices {
+		if p.Timestamp > latest {
+			latest = p.Timestamp
+		}
+	}
+	if latest == 0 || now.Unix() <= latest {
+		return 0
+	}
+	return now.Sub(time.Unix(latest, 0))
+}
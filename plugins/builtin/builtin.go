@@ -0,0 +1,16 @@
+// Package builtin links the plugins which can run in the oracle server process, a plugin is linked by importing the
+// package that registers its adapter with plugins/common.RegisterAdapter. The linked plugins are still launched from
+// their binaries unless the inProcess flag is set in their plugin configs.
+package builtin
+
+import (
+	_ "autonity-oracle/plugins/crypto_coinbase/common"      // crypto_coinbase
+	_ "autonity-oracle/plugins/crypto_coingecko/common"     // crypto_coingecko
+	_ "autonity-oracle/plugins/crypto_kraken/common"        // crypto_kraken
+	_ "autonity-oracle/plugins/crypto_uniswap/common"       // crypto_uniswap
+	_ "autonity-oracle/plugins/forex_currencyfreaks/common" // forex_currencyfreaks
+	_ "autonity-oracle/plugins/forex_currencylayer/common"  // forex_currencylayer
+	_ "autonity-oracle/plugins/forex_exchangerate/common"   // forex_exchangerate
+	_ "autonity-oracle/plugins/forex_openexchange/common"   // forex_openexchange
+	_ "autonity-oracle/plugins/forex_wise/common"           // forex_wise
+)
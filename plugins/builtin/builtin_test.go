@@ -0,0 +1,22 @@
+package builtin
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestBuiltinAdapters(t *testing.T) {
+	require.ElementsMatch(t, []string{"crypto_coinbase", "crypto_coingecko", "crypto_kraken", "crypto_uniswap",
+		"forex_currencyfreaks", "forex_currencylayer", "forex_exchangerate", "forex_openexchange", "forex_wise"},
+		common.RegisteredAdapters())
+
+	adapter, err := common.NewInProcessAdapter("forex_exchangerate", &config.PluginConfig{Name: "forex_exchangerate", Key: "test-key"})
+	require.NoError(t, err)
+	defer adapter.Close()
+	state, err := adapter.State(0)
+	require.NoError(t, err)
+	require.True(t, state.KeyRequired)
+	require.Equal(t, common.DefaultForexSymbols, state.AvailableSymbols)
+}
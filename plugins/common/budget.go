@@ -0,0 +1,255 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultWarnRatio = 0.8
+
+var ErrBudgetExceeded = fmt.Errorf("%w: the request budget is exceeded", ErrAccessLimited)
+
+// the quota headers of the data providers, the first one present is taken.
+var (
+	remainingHeaders = []string{"X-RateLimit-Remaining", "RateLimit-Remaining", "X-RateLimit-Requests-Remaining"}
+	limitHeaders     = []string{"X-RateLimit-Limit", "RateLimit-Limit", "X-RateLimit-Requests-Limit"}
+	resetHeaders     = []string{"X-RateLimit-Reset", "RateLimit-Reset", "X-RateLimit-Requests-Reset"}
+)
+
+// budgetUsage is the usage of the billing period, it is persisted in the state file.
+type budgetUsage struct {
+	PeriodStart int64 `json:"periodStart"`
+	Used        int   `json:"used"`
+	LastRequest int64 `json:"lastRequest"`
+}
+
+// Budget guards the requests to a data provider with a token bucket and a monthly budget. The requests of the monthly
+// budget are spaced evenly over the rest of the billing period, thus the budget lasts the period. The quota headers of
+// the data provider pause the requests once the provider's quota is exhausted, and the exhaustion of both budgets is
+// warned ahead.
+type Budget struct {
+	lock   sync.Mutex
+	conf   config.RequestBudget
+	logger hclog.Logger
+	now    func() time.Time
+
+	tokens   float64
+	refilled time.Time
+
+	usage       budgetUsage
+	warned      bool
+	quotaWarned bool
+	pausedUntil time.Time
+}
+
+func NewBudget(conf config.RequestBudget, logger hclog.Logger) *Budget {
+	if conf.Rate > 0 && conf.Burst == 0 {
+		conf.Burst = 1
+	}
+	if conf.BillingDay == 0 {
+		conf.BillingDay = 1
+	}
+	if conf.WarnRatio == 0 {
+		conf.WarnRatio = defaultWarnRatio
+	}
+
+	b := &Budget{conf: conf, logger: logger, now: time.Now, tokens: float64(conf.Burst)}
+	b.refilled = b.now()
+	if err := b.load(); err != nil {
+		logger.Warn("cannot load the request budget usage, it is counted from zero", "file", conf.StateFile, "error", err.Error())
+	}
+	return b
+}
+
+// Acquire takes a request from the budget, it returns ErrBudgetExceeded if the request is not granted.
+func (b *Budget) Acquire() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+
+	if now.Before(b.pausedUntil) {
+		return fmt.Errorf("%w: the quota of the data provider is exhausted until %s", ErrBudgetExceeded,
+			b.pausedUntil.UTC().Format(time.RFC3339))
+	}
+
+	if b.conf.Rate > 0 {
+		b.tokens = math.Min(float64(b.conf.Burst), b.tokens+now.Sub(b.refilled).Seconds()*b.conf.Rate)
+		b.refilled = now
+		if b.tokens < 1 {
+			return fmt.Errorf("%w: the rate of %g requests per second is reached", ErrBudgetExceeded, b.conf.Rate)
+		}
+	}
+
+	if b.conf.Monthly > 0 {
+		start, end := billingPeriod(now, b.conf.BillingDay)
+		if b.usage.PeriodStart != start.Unix() {
+			b.usage = budgetUsage{PeriodStart: start.Unix()}
+			b.warned = false
+		}
+		remaining := b.conf.Monthly - b.usage.Used
+		if remaining <= 0 {
+			return fmt.Errorf("%w: the monthly budget of %d requests is exhausted until %s", ErrBudgetExceeded,
+				b.conf.Monthly, end.Format(time.RFC3339))
+		}
+		spacing := end.Sub(now) / time.Duration(remaining)
+		if last := time.Unix(b.usage.LastRequest, 0); b.usage.LastRequest != 0 && now.Sub(last) < spacing {
+			return fmt.Errorf("%w: the requests are spaced by %s to last the billing period", ErrBudgetExceeded,
+				spacing.Round(time.Second))
+		}
+	}
+
+	if b.conf.Rate > 0 {
+		b.tokens--
+	}
+	if b.conf.Monthly > 0 {
+		b.usage.Used++
+		b.usage.LastRequest = now.Unix()
+		b.warnUsage(now)
+		if err := b.save(); err != nil {
+			b.logger.Warn("cannot save the request budget usage", "file", b.conf.StateFile, "error", err.Error())
+		}
+	}
+	return nil
+}
+
+func (b *Budget) warnUsage(now time.Time) {
+	_, end := billingPeriod(now, b.conf.BillingDay)
+	switch {
+	case b.usage.Used == b.conf.Monthly:
+		b.logger.Warn("the monthly request budget is exhausted", "used", b.usage.Used, "until", end)
+	case !b.warned && float64(b.usage.Used) >= b.conf.WarnRatio*float64(b.conf.Monthly):
+		b.warned = true
+		b.logger.Warn("the monthly request budget is running out", "used", b.usage.Used, "monthly", b.conf.Monthly,
+			"until", end)
+	}
+}
+
+// Observe reads the quota headers of the data provider from the response, the requests are paused until the quota is
+// reset once it is exhausted, or until the time asked by the Retry-After header of a rate limited response.
+func (b *Budget) Observe(res *http.Response) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	now := b.now()
+
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode == http.StatusServiceUnavailable {
+		if until, ok := parseRetryAfter(res.Header.Get("Retry-After"), now); ok {
+			b.pause(until)
+		}
+	}
+
+	remaining, ok := headerInt(res.Header, remainingHeaders)
+	if !ok {
+		return
+	}
+	limit, hasLimit := headerInt(res.Header, limitHeaders)
+	if remaining <= 0 {
+		if reset, ok := headerInt(res.Header, resetHeaders); ok {
+			b.pause(resetTime(reset, now))
+		}
+		return
+	}
+
+	if !hasLimit || limit <= 0 {
+		return
+	}
+	if float64(limit-remaining) < b.conf.WarnRatio*float64(limit) {
+		b.quotaWarned = false
+		return
+	}
+	if !b.quotaWarned {
+		b.quotaWarned = true
+		b.logger.Warn("the quota of the data provider is running out", "remaining", remaining, "limit", limit)
+	}
+}
+
+func (b *Budget) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+		b.logger.Warn("the requests to the data provider are paused", "until", until)
+	}
+}
+
+func (b *Budget) load() error {
+	if b.conf.StateFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(b.conf.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &b.usage)
+}
+
+// save writes the usage into a temp file then renames it, thus the state file is not corrupted by a crash.
+func (b *Budget) save() error {
+	if b.conf.StateFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(b.usage)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(b.conf.StateFile), 0700); err != nil {
+		return err
+	}
+	tmp := b.conf.StateFile + ".tmp"
+	if err = os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.conf.StateFile)
+}
+
+// billingPeriod returns the start and the end of the billing period in UTC that the time is in.
+func billingPeriod(now time.Time, billingDay int) (time.Time, time.Time) {
+	now = now.UTC()
+	start := time.Date(now.Year(), now.Month(), billingDay, 0, 0, 0, 0, time.UTC)
+	if now.Before(start) {
+		start = start.AddDate(0, -1, 0)
+	}
+	return start, start.AddDate(0, 1, 0)
+}
+
+func headerInt(header http.Header, keys []string) (int64, bool) {
+	for _, k := range keys {
+		if v := header.Get(k); v != "" {
+			n, err := strconv.ParseInt(v, 10, 64)
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
+
+// resetTime resolves the reset of a quota header, which is either the seconds to the reset or a unix timestamp.
+func resetTime(reset int64, now time.Time) time.Time {
+	if reset > 1_000_000_000 {
+		return time.Unix(reset, 0)
+	}
+	return now.Add(time.Duration(reset) * time.Second)
+}
+
+// parseRetryAfter parses the Retry-After header, which is either the seconds to wait or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Time, bool) {
+	if v == "" {
+		return time.Time{}, false
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return now.Add(time.Duration(secs) * time.Second), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,133 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestBudget(conf config.RequestBudget, now *time.Time) *Budget {
+	b := NewBudget(conf, hclog.NewNullLogger())
+	b.now = func() time.Time { return *now }
+	b.refilled = *now
+	return b
+}
+
+func TestBillingPeriod(t *testing.T) {
+	start, end := billingPeriod(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC), 15)
+	require.Equal(t, time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = billingPeriod(time.Date(2026, 12, 20, 0, 0, 0, 0, time.UTC), 1)
+	require.Equal(t, time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC), start)
+	require.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), end)
+}
+
+func TestBudget(t *testing.T) {
+	t.Run("test token bucket", func(t *testing.T) {
+		now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		b := newTestBudget(config.RequestBudget{Rate: 0.5, Burst: 2}, &now)
+		require.NoError(t, b.Acquire())
+		require.NoError(t, b.Acquire())
+		require.ErrorIs(t, b.Acquire(), ErrAccessLimited)
+
+		now = now.Add(2 * time.Second)
+		require.NoError(t, b.Acquire())
+		require.ErrorIs(t, b.Acquire(), ErrBudgetExceeded)
+	})
+
+	t.Run("test monthly budget is spaced and persisted", func(t *testing.T) {
+		// 22 days and 10 requests are left in the billing period, thus the requests are spaced by 2.2 days.
+		now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		conf := config.RequestBudget{Monthly: 10, StateFile: filepath.Join(t.TempDir(), "budget.json")}
+		b := newTestBudget(conf, &now)
+		require.NoError(t, b.Acquire())
+		now = now.Add(48 * time.Hour)
+		require.ErrorIs(t, b.Acquire(), ErrBudgetExceeded)
+		now = now.Add(8 * time.Hour)
+		require.NoError(t, b.Acquire())
+
+		// the usage is loaded on restart.
+		b = newTestBudget(conf, &now)
+		require.Equal(t, 2, b.usage.Used)
+		require.ErrorIs(t, b.Acquire(), ErrBudgetExceeded)
+
+		// the budget is exhausted until the next billing period.
+		b.usage.Used = 10
+		now = now.Add(7 * 24 * time.Hour)
+		require.ErrorContains(t, b.Acquire(), "exhausted")
+		now = time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, b.Acquire())
+		require.Equal(t, 1, b.usage.Used)
+	})
+
+	t.Run("test provider quota headers", func(t *testing.T) {
+		now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		b := newTestBudget(config.RequestBudget{Rate: 100, Burst: 100}, &now)
+
+		res := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+		res.Header.Set("X-RateLimit-Limit", "100")
+		res.Header.Set("X-RateLimit-Remaining", "10")
+		b.Observe(res)
+		require.True(t, b.quotaWarned)
+		require.NoError(t, b.Acquire())
+
+		res.Header.Set("X-RateLimit-Remaining", "0")
+		res.Header.Set("X-RateLimit-Reset", "60")
+		b.Observe(res)
+		require.ErrorContains(t, b.Acquire(), "quota of the data provider")
+		now = now.Add(time.Minute)
+		require.NoError(t, b.Acquire())
+
+		res = &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		res.Header.Set("Retry-After", "30")
+		b.Observe(res)
+		require.ErrorIs(t, b.Acquire(), ErrBudgetExceeded)
+		now = now.Add(30 * time.Second)
+		require.NoError(t, b.Acquire())
+	})
+}
+
+func TestNewPluginClient(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	conf := &config.PluginConfig{Name: "test", Timeout: 5, Endpoint: strings.TrimPrefix(server.URL, "http://"),
+		Budget: config.RequestBudget{Rate: 0.001}}
+	client := NewPluginClient(conf)
+	defer client.Conn.Close()
+
+	res, err := client.Conn.Request("http", &url.URL{Path: "/"})
+	require.NoError(t, err)
+	res.Body.Close()
+	_, err = client.Conn.Request("http", &url.URL{Path: "/"})
+	require.ErrorIs(t, err, ErrAccessLimited)
+	require.Equal(t, 1, hits)
+
+	// each retry takes a request from the budget.
+	conf.Budget = config.RequestBudget{Rate: 0.001, Burst: 2}
+	conf.HTTP = config.HTTPConfig{Retries: 3, RetryBackoff: 1}
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	conf.Endpoint = strings.TrimPrefix(failing.URL, "http://")
+	client = NewPluginClient(conf)
+	defer client.Conn.Close()
+	hits = 0
+	_, err = client.Conn.Request("http", &url.URL{Path: "/"})
+	require.ErrorIs(t, err, ErrAccessLimited)
+	require.Equal(t, 2, hits)
+}
@@ -1,8 +1,13 @@
 package common
 
 import (
+	"autonity-oracle/config"
+	"autonity-oracle/types"
+	"github.com/hashicorp/go-hclog"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
 	"time"
 )
 
@@ -19,9 +24,32 @@ type DataSourceClient interface {
 	Close()
 }
 
+// PriceNotifier is the optional capability of a DataSourceClient which maintains live prices internally, for example
+// from the swap events of an AMM or from a web socket feed. The channel is signaled once the prices are updated, thus
+// the plugin can push them to the oracle server instead of waiting to be polled.
+type PriceNotifier interface {
+	PriceUpdates() <-chan struct{}
+}
+
+// HistoricalClient is the optional capability of a DataSourceClient which can fetch the prices at a given time, for
+// example from the candles of an exchange. The plugin serves it to the oracle server to backfill the samples that
+// missed the target of a round.
+type HistoricalClient interface {
+	FetchPriceAt(symbols []string, ts int64) (Prices, error)
+}
+
 type connection struct {
 	client *http.Client
 	host   string
+
+	// the HTTP client settings of the plugin, the error of an invalid setting fails the requests.
+	timeout         time.Duration // the deadline of a request with its retries.
+	budget          *Budget       // the request budget of the data provider, it is taken by each attempt.
+	err             error
+	retries         int
+	backoff         time.Duration
+	headers         map[string]string
+	maxResponseSize int64
 }
 
 func NewConnection(duration time.Duration, host string) Connection {
@@ -43,11 +71,25 @@ func (conn *connection) Request(scheme string, endpoint *url.URL) (*http.Respons
 	endpoint.Scheme = scheme
 	endpoint.Host = conn.host
 	targetUrl := endpoint.String()
-	return conn.client.Get(targetUrl)
+	req, err := http.NewRequest(http.MethodGet, targetUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	return conn.do(req)
 }
 
 func (conn *connection) Do(req *http.Request) (*http.Response, error) {
-	return conn.client.Do(req)
+	return conn.do(req)
+}
+
+// observeResponse counts the status codes and the errors of the HTTP requests into the process metrics.
+func observeResponse(res *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		ProcessMetrics.Inc(MetricHTTPErrors, 1)
+		return res, err
+	}
+	ProcessMetrics.Inc(MetricHTTPStatus+strconv.Itoa(res.StatusCode), 1)
+	return res, nil
 }
 
 type Client struct {
@@ -59,6 +101,39 @@ func NewClient(apiKey string, timeOut time.Duration, host string) *Client {
 	return NewClientConnection(apiKey, NewConnection(timeOut, host))
 }
 
+// NewPluginClient creates the client of the data provider with the HTTP client settings of the plugin config, the
+// requests are taken from the request budget of the plugin config if there is one, and each retry of a request takes
+// one more request from the budget.
+func NewPluginClient(conf *config.PluginConfig) *Client {
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:       conf.Name,
+		Level:      hclog.Info,
+		Output:     os.Stderr,
+		JSONFormat: true,
+	})
+
+	httpConn := newHTTPConnection(time.Second*time.Duration(conf.Timeout), conf.Endpoint, conf.HTTP)
+	if httpConn.err != nil {
+		logger.Error("the requests to the data provider are failed", "error", httpConn.err.Error())
+	}
+
+	if conf.Budget != (config.RequestBudget{}) {
+		httpConn.budget = NewBudget(conf.Budget, logger)
+	}
+	return NewClientConnection(conf.Key, httpConn)
+}
+
+// ClientConfigFields are the config schema fields of the client created by NewPluginClient, they are published by the
+// plugins using it.
+func ClientConfigFields() []types.ConfigField {
+	return []types.ConfigField{
+		{Name: "budget", Type: types.ConfigObject,
+			Description: "the request budget of the data provider: rate, burst, monthly, billingDay, warnRatio and stateFile"},
+		{Name: "http", Type: types.ConfigObject,
+			Description: "the http client settings: retries, retryBackoff, proxy, caFile, certFile, keyFile, headers and maxResponseSize"},
+	}
+}
+
 func NewClientConnection(apiKey string, connection Connection) *Client {
 	return &Client{
 		Conn:   connection,
@@ -4,15 +4,19 @@ import (
 	"autonity-oracle/config"
 	"autonity-oracle/types"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-plugin"
 	"github.com/shopspring/decimal"
+	"io"
 	"math/big"
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -35,6 +39,22 @@ const (
 	AutonityCryptoDecimals       = 18 // both NTN and the Wrapped ATN take 18 as the decimal.
 	USDCDecimals                 = 6  // the decimal of USDC coin in autonity L1 network.
 	CryptoToUsdcDecimals         = 18 // the data precision in oracle contract.
+
+	// the names of the plugin-side metrics.
+	MetricFetchErrors   = "fetch/errors"
+	MetricRateLimited   = "fetch/rate_limited"
+	MetricCacheHits     = "cache/hits"
+	MetricCacheMisses   = "cache/misses"
+	MetricCacheHitRatio = "cache/hit_ratio"
+	MetricHTTPErrors    = "http/errors"
+	MetricHTTPStatus    = "http/status/"
+
+	// streamHeartbeat is the interval to push a heartbeat to the oracle server on a quiet data source, it keeps the
+	// stream fresh in the oracle server side.
+	streamHeartbeat = 5 * time.Second
+	// streamRefresh is the interval to re-push an unchanged price, it is shorter than the TTL of the samples in the
+	// oracle server, thus the price of a quiet market does not expire there.
+	streamRefresh = 15 * time.Second
 )
 
 type Price struct {
@@ -55,6 +75,18 @@ type Plugin struct {
 	cachePrices      map[string]types.Price
 	chainID          *big.Int // piccadilly, bakerloo, mainnet, or nil for common.
 	dataSourceType   types.DataSourceType
+
+	// the plugin-side metrics carried by the price reports, and if the metrics of the plugin process are carried too.
+	metrics        *Metrics
+	processMetrics bool
+
+	// the defaults and the client specific fields of the published config schema, it is not published if it is nil.
+	defConf      *config.PluginConfig
+	schemaFields []types.ConfigField
+
+	lockPrices sync.Mutex // the prices are fetched by both the RPC of the oracle server and the price stream.
+	lockStream sync.Mutex
+	stopStream chan struct{}
 }
 
 func NewPlugin(conf *config.PluginConfig, client DataSourceClient, version string, srcType types.DataSourceType, chainID *big.Int) *Plugin {
@@ -65,7 +97,7 @@ func NewPlugin(conf *config.PluginConfig, client DataSourceClient, version strin
 		JSONFormat: true,
 	})
 
-	return &Plugin{
+	p := &Plugin{
 		version:          version,
 		logger:           logger,
 		client:           client,
@@ -74,10 +106,40 @@ func NewPlugin(conf *config.PluginConfig, client DataSourceClient, version strin
 		cachePrices:      make(map[string]types.Price),
 		chainID:          chainID,
 		dataSourceType:   srcType,
+		metrics:          NewMetrics(),
+		processMetrics:   true,
 	}
+
+	if recorder, ok := client.(MetricsRecorder); ok {
+		recorder.SetMetrics(p.metrics)
+	}
+	return p
 }
 
 func (p *Plugin) FetchPrices(symbols []string) (types.PluginPriceReport, error) {
+	report, err := p.fetchPrices(symbols, true)
+	report.Metrics = p.Metrics()
+	return report, err
+}
+
+// ReportMetrics reports the plugin-side metrics on the poll of the oracle server, it covers the plugin whose fetches
+// fail, as the report of a failed fetch is not delivered, and the streaming plugin which is not polled for prices.
+func (p *Plugin) ReportMetrics() ([]types.Metric, error) {
+	return p.Metrics(), nil
+}
+
+// Metrics returns the plugin-side metrics carried by the price reports.
+func (p *Plugin) Metrics() []types.Metric {
+	snapshot := p.metrics.Snapshot()
+	if p.processMetrics {
+		snapshot = append(snapshot, ProcessMetrics.Snapshot()...)
+	}
+	return snapshot
+}
+
+func (p *Plugin) fetchPrices(symbols []string, useCache bool) (types.PluginPriceReport, error) {
+	p.lockPrices.Lock()
+	defer p.lockPrices.Unlock()
 	var report types.PluginPriceReport
 
 	availableSymbols, unRecognizableSymbols, availableSymMap := p.resolveSymbols(symbols)
@@ -86,22 +148,73 @@ func (p *Plugin) FetchPrices(symbols []string) (types.PluginPriceReport, error)
 		return report, ErrKnownSymbols
 	}
 
-	cPRs, err := p.fetchPricesFromCache(availableSymbols)
-	if err == nil {
-		report.Prices = cPRs
-		report.UnRecognizableSymbols = unRecognizableSymbols
-		return report, nil
+	if useCache {
+		cPRs, err := p.fetchPricesFromCache(availableSymbols, availableSymMap)
+		p.updateCacheMetrics(err == nil)
+		if err == nil {
+			report.Prices = cPRs
+			report.UnRecognizableSymbols = unRecognizableSymbols
+			return report, nil
+		}
 	}
 
 	// fetch data from data source.
 	res, err := p.client.FetchPrice(availableSymbols)
+	if errors.Is(err, ErrBudgetExceeded) {
+		// the request is held back by the budget rather than failed by the data source, thus the last prices are
+		// served with their original timestamps until the budget grants the next request.
+		p.metrics.Inc(MetricRateLimited, 1)
+		p.logger.Debug("serve the cached prices", "reason", err.Error())
+		report.Prices = p.lastPrices(availableSymbols, availableSymMap)
+		report.UnRecognizableSymbols = unRecognizableSymbols
+		return report, nil
+	}
 	if err != nil {
+		p.metrics.Inc(MetricFetchErrors, 1)
+		if errors.Is(err, ErrAccessLimited) {
+			p.metrics.Inc(MetricRateLimited, 1)
+		}
 		return report, err
 	}
 
 	p.logger.Info("sampled data", "data", res)
 
-	now := time.Now().Unix()
+	report.Prices = p.convertPrices(res, availableSymMap, time.Now().Unix())
+	for _, pr := range report.Prices {
+		p.cachePrices[pr.Symbol] = pr
+	}
+	report.UnRecognizableSymbols = unRecognizableSymbols
+	return report, nil
+}
+
+// FetchPriceAt fetches the prices of the symbols at the timestamp, if the data source client implements the
+// HistoricalClient. The prices are not cached, as they are not the latest ones.
+func (p *Plugin) FetchPriceAt(symbols []string, ts int64) (types.PluginPriceReport, error) {
+	var report types.PluginPriceReport
+	client, ok := p.client.(HistoricalClient)
+	if !ok {
+		return report, types.ErrHistoricalUnsupported
+	}
+
+	availableSymbols, unRecognizableSymbols, availableSymMap := p.resolveSymbols(symbols)
+	report.UnRecognizableSymbols = unRecognizableSymbols
+	if len(availableSymbols) == 0 {
+		return report, ErrKnownSymbols
+	}
+
+	res, err := client.FetchPriceAt(availableSymbols, ts)
+	if err != nil {
+		return report, err
+	}
+
+	p.logger.Info("sampled historical data", "ts", ts, "data", res)
+	report.Prices = p.convertPrices(res, availableSymMap, ts)
+	return report, nil
+}
+
+// convertPrices converts the prices of the data source into the prices of the symbols used in oracle server side.
+func (p *Plugin) convertPrices(res Prices, symbolsMapping map[string]string, ts int64) []types.Price {
+	var prices []types.Price
 	for _, v := range res {
 		decPrice, err := decimal.NewFromString(v.Price)
 		if err != nil {
@@ -115,17 +228,23 @@ func (p *Plugin) FetchPrices(symbols []string) (types.PluginPriceReport, error)
 			continue
 		}
 
-		pr := types.Price{
-			Timestamp: now,
-			Symbol:    availableSymMap[v.Symbol], // set the symbol with the symbol style used in oracle server side.
+		symbol := symbolsMapping[v.Symbol] // set the symbol with the symbol style used in oracle server side.
+		if _, inverted, ok := p.conf.SymbolMap.Ticker(symbol); ok && inverted {
+			if decPrice.IsZero() {
+				p.logger.Error("cannot invert zero price", "symbol", symbol, "ticker", v.Symbol)
+				continue
+			}
+			decPrice = decimal.NewFromInt(1).DivRound(decPrice, CryptoToUsdcDecimals)
+		}
+
+		prices = append(prices, types.Price{
+			Timestamp: ts,
+			Symbol:    symbol,
 			Price:     decPrice,
 			Volume:    decVol,
-		}
-		p.cachePrices[v.Symbol] = pr
-		report.Prices = append(report.Prices, pr)
+		})
 	}
-	report.UnRecognizableSymbols = unRecognizableSymbols
-	return report, nil
+	return prices
 }
 
 func (p *Plugin) State(chainID int64) (types.PluginStatement, error) {
@@ -155,10 +274,15 @@ func (p *Plugin) State(chainID int64) (types.PluginStatement, error) {
 	}
 
 	state.Version = p.version
-	state.AvailableSymbols = symbols
+	state.AvailableSymbols = p.statedSymbols(symbols)
 	state.KeyRequired = p.client.KeyRequired()
 	state.DataSource = p.conf.Scheme + "://" + p.conf.Endpoint
 	state.DataSourceType = p.dataSourceType
+	state.Symbols = p.symbolMetadata(state.AvailableSymbols)
+	_, state.Streaming = p.client.(PriceNotifier)
+	_, state.Historical = p.client.(HistoricalClient)
+	state.ReportsMetrics = true
+	state.ConfigSchema = p.configSchema()
 
 	if p.chainID != nil && p.chainID.Int64() != chainID {
 		return state, ErrChainIDMismatch
@@ -167,14 +291,158 @@ func (p *Plugin) State(chainID int64) (types.PluginStatement, error) {
 	return state, nil
 }
 
+// StreamPrices pushes the changed prices of the symbols into the sink once the data source client notifies the updates,
+// and it pushes a heartbeat without the unchanged prices periodically, thus the prices are not sampled twice. The
+// previous stream is stopped, as there is only one subscriber.
+func (p *Plugin) StreamPrices(symbols []string, sink types.PriceSink) error {
+	notifier, ok := p.client.(PriceNotifier)
+	if !ok {
+		return types.ErrStreamingUnsupported
+	}
+
+	p.lockStream.Lock()
+	defer p.lockStream.Unlock()
+	if p.stopStream != nil {
+		close(p.stopStream)
+	}
+	p.stopStream = make(chan struct{})
+	go p.stream(symbols, sink, notifier.PriceUpdates(), p.stopStream)
+	return nil
+}
+
+func (p *Plugin) stream(symbols []string, sink types.PriceSink, updates <-chan struct{}, stop chan struct{}) {
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	if closer, ok := sink.(io.Closer); ok {
+		defer closer.Close() //nolint
+	}
+
+	pushed := make(map[string]pushedPrice)
+	for {
+		isHeartbeat := false
+		select {
+		case <-stop:
+			return
+		case <-updates:
+		case <-heartbeat.C:
+			isHeartbeat = true
+		}
+
+		report, err := p.fetchPrices(symbols, false)
+		if err != nil {
+			p.logger.Debug("no prices to be streamed", "error", err)
+		}
+
+		now := time.Now()
+		prices := changedPrices(report.Prices, pushed, now)
+		if len(prices) == 0 && !isHeartbeat {
+			continue
+		}
+
+		if err = sink.PushPrices(prices); err != nil {
+			// the oracle server is congested, drop the prices as the latest ones are pushed on the next update.
+			if errors.Is(err, types.ErrStreamBackpressure) {
+				p.logger.Debug("price stream backpressure, prices are dropped")
+				continue
+			}
+			// the oracle server re-subscribes once the stream is stale.
+			p.logger.Warn("price stream is broken", "error", err.Error())
+			return
+		}
+		for _, price := range prices {
+			pushed[price.Symbol] = pushedPrice{price: price, at: now}
+		}
+	}
+}
+
+// pushedPrice is the last price of a symbol being pushed into the stream.
+type pushedPrice struct {
+	price types.Price
+	at    time.Time
+}
+
+// changedPrices returns the prices that differ from the pushed ones, an unchanged price is returned once its last push
+// is older than the refresh interval.
+func changedPrices(prices []types.Price, pushed map[string]pushedPrice, now time.Time) []types.Price {
+	var changed []types.Price
+	for _, price := range prices {
+		last, ok := pushed[price.Symbol]
+		if ok && now.Sub(last.at) < streamRefresh && last.price.Timestamp == price.Timestamp &&
+			last.price.Price.Equal(price.Price) {
+			continue
+		}
+		changed = append(changed, price)
+	}
+	return changed
+}
+
 func (p *Plugin) Close() {
+	p.lockStream.Lock()
+	if p.stopStream != nil {
+		close(p.stopStream)
+		p.stopStream = nil
+	}
+	p.lockStream.Unlock()
+
 	if p.client != nil {
 		p.client.Close()
 	}
 }
 
+// symbolMetadata states the symbols in the style of the oracle server, the forex markets are closed on weekends, and the
+// NTN-ATN price is derived from the NTN and ATN prices.
+func (p *Plugin) symbolMetadata(symbols []string) []types.SymbolMetadata {
+	metadata := make([]types.SymbolMetadata, 0, len(symbols))
+	for _, s := range symbols {
+		symbol := ConvertSymbol(s, "-")
+		meta := types.SymbolMetadata{
+			Symbol:         symbol,
+			UpdateInterval: int64(p.conf.DataUpdateInterval),
+			Derived:        symbol == NTNATNSymbol,
+		}
+		if parts := strings.Split(symbol, "-"); len(parts) == 2 {
+			meta.QuoteCurrency = parts[1]
+		}
+		for _, forex := range DefaultForexSymbols {
+			if symbol == forex {
+				meta.MarketHours = types.MarketWeekdays
+				break
+			}
+		}
+		metadata = append(metadata, meta)
+	}
+	return metadata
+}
+
+// statedSymbols replaces the tickers of the data provider mapped by the symbol map with their protocol symbols.
+func (p *Plugin) statedSymbols(symbols []string) []string {
+	if len(p.conf.SymbolMap) == 0 {
+		return symbols
+	}
+
+	stated := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		if symbol, ok := p.mappedSymbol(s); ok {
+			s = symbol
+		}
+		stated = append(stated, s)
+	}
+	return stated
+}
+
+// mappedSymbol returns the protocol symbol which maps the ticker of the data provider in the symbol map.
+func (p *Plugin) mappedSymbol(ticker string) (string, bool) {
+	for symbol, t := range p.conf.SymbolMap {
+		if t, _ = config.ParseTicker(t); t == ticker {
+			return symbol, true
+		}
+	}
+	return "", false
+}
+
 // resolveSymbols resolve supported symbols of provider, and it builds the mapping of symbols from `-` separated pattern to those
-// pattens supported by data providers, and filter outs those un-supported symbols.
+// pattens supported by data providers, and filter outs those un-supported symbols. The symbols of the symbol map are
+// mapped explicitly, and the tickers mapped by them are not resolved for the other symbols.
 func (p *Plugin) resolveSymbols(askedSymbols []string) ([]string, []string, map[string]string) {
 	var supported []string
 	var unRecognizable []string
@@ -182,7 +450,14 @@ func (p *Plugin) resolveSymbols(askedSymbols []string) ([]string, []string, map[
 	symbolsMapping := make(map[string]string)
 
 	for _, askedSym := range askedSymbols {
-		converted := ConvertSymbol(askedSym, p.symbolSeparator)
+		converted, _, mapped := p.conf.SymbolMap.Ticker(askedSym)
+		if !mapped {
+			converted = ConvertSymbol(askedSym, p.symbolSeparator)
+			if _, ok := p.mappedSymbol(converted); ok {
+				unRecognizable = append(unRecognizable, askedSym)
+				continue
+			}
+		}
 		if _, ok := p.availableSymbols[converted]; !ok {
 			unRecognizable = append(unRecognizable, askedSym)
 			continue
@@ -193,11 +468,21 @@ func (p *Plugin) resolveSymbols(askedSymbols []string) ([]string, []string, map[
 	return supported, unRecognizable, symbolsMapping
 }
 
-func (p *Plugin) fetchPricesFromCache(availableSymbols []string) ([]types.Price, error) {
+func (p *Plugin) updateCacheMetrics(hit bool) {
+	if hit {
+		p.metrics.Inc(MetricCacheHits, 1)
+	} else {
+		p.metrics.Inc(MetricCacheMisses, 1)
+	}
+	hits, misses := p.metrics.Counter(MetricCacheHits), p.metrics.Counter(MetricCacheMisses)
+	p.metrics.Update(MetricCacheHitRatio, float64(hits)/float64(hits+misses))
+}
+
+func (p *Plugin) fetchPricesFromCache(availableSymbols []string, symbolsMapping map[string]string) ([]types.Price, error) {
 	var prices []types.Price
 	now := time.Now().Unix()
 	for _, s := range availableSymbols {
-		pr, ok := p.cachePrices[s]
+		pr, ok := p.cachePrices[symbolsMapping[s]]
 		if !ok {
 			return nil, fmt.Errorf("no data buffered")
 		}
@@ -211,12 +496,33 @@ func (p *Plugin) fetchPricesFromCache(availableSymbols []string) ([]types.Price,
 	return prices, nil
 }
 
-// LoadPluginConf is called from plugin main() to load plugin's conf from system env.
+// lastPrices returns the cached prices of the symbols regardless of their age, the symbols never sampled are skipped.
+func (p *Plugin) lastPrices(availableSymbols []string, symbolsMapping map[string]string) []types.Price {
+	var prices []types.Price
+	for _, s := range availableSymbols {
+		if pr, ok := p.cachePrices[symbolsMapping[s]]; ok {
+			prices = append(prices, pr)
+		}
+	}
+	return prices
+}
+
+// LoadPluginConf is called from plugin main() to load plugin's conf from the file passed by the oracle server, the conf
+// in the system env is still loaded if there is no such file, to be compatible with the legacy oracle servers.
 func LoadPluginConf(cmd string) (*config.PluginConfig, error) {
-	name := filepath.Base(cmd)
-	conf := os.Getenv(name)
+	var conf []byte
+	if path := os.Getenv(types.PluginConfFileEnv); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		conf = data
+	} else {
+		conf = []byte(os.Getenv(filepath.Base(cmd)))
+	}
+
 	var c config.PluginConfig
-	err := json.Unmarshal([]byte(conf), &c)
+	err := json.Unmarshal(conf, &c)
 	if err != nil {
 		return nil, err
 	}
@@ -250,6 +556,12 @@ func ResolveConf(cmd string, defConf *config.PluginConfig) *config.PluginConfig
 		os.Exit(-1)
 	}
 
+	applyDefaultConf(conf, defConf)
+	return conf
+}
+
+// applyDefaultConf fills the fields of the plugin's conf which are omitted by the oracle server with the default conf.
+func applyDefaultConf(conf *config.PluginConfig, defConf *config.PluginConfig) {
 	if conf.Timeout == 0 {
 		conf.Timeout = defConf.Timeout
 	}
@@ -290,18 +602,54 @@ func ResolveConf(cmd string, defConf *config.PluginConfig) *config.PluginConfig
 		conf.SwapAddress = defConf.SwapAddress
 	}
 
-	return conf
+	if len(conf.SymbolMap) == 0 {
+		conf.SymbolMap = defConf.SymbolMap
+	}
 }
 
-// PluginServe doesn't return until the plugin is done being executed.
-func PluginServe(p *Plugin) {
-	var pluginMap = map[string]plugin.Plugin{
-		"adapter": &types.AdapterPlugin{Impl: p},
+// PublishConfigSchema publishes the schema of the plugin config in the plugin's statement, thus the oracle server can
+// validate the plugin config on the launch. The schema covers the fields used by the Plugin with the defaults of the
+// plugin, and the fields specific to the data source client.
+func (p *Plugin) PublishConfigSchema(defConf *config.PluginConfig, fields ...types.ConfigField) {
+	p.defConf = defConf
+	p.schemaFields = fields
+}
+
+func (p *Plugin) configSchema() []types.ConfigField {
+	if p.defConf == nil {
+		return nil
+	}
+
+	// the default of a secret is not published, the key is required only if the plugin does not have a default one.
+	schema := []types.ConfigField{
+		{Name: "key", Type: types.ConfigString, Required: p.client.KeyRequired() && p.defConf.Key == "", Secret: true,
+			Description: "the API key granted by the data provider"},
+		{Name: "scheme", Type: types.ConfigString, Required: true, Default: p.defConf.Scheme,
+			Description: "the scheme of the data source"},
+		{Name: "endpoint", Type: types.ConfigString, Required: true, Default: p.defConf.Endpoint,
+			Description: "the endpoint of the data source"},
+		{Name: "timeout", Type: types.ConfigInt, Default: defaultInt(p.defConf.Timeout),
+			Description: "the timeout in seconds of a request to the data source"},
+		{Name: "refresh", Type: types.ConfigInt, Default: defaultInt(p.defConf.DataUpdateInterval),
+			Description: "the interval in seconds to refresh the prices from the data source"},
+		{Name: "symbolMap", Type: types.ConfigMap,
+			Description: "the protocol symbols mapped to the tickers of the data source, a ticker prefixed with 1/ is inverted"},
+	}
+	return append(schema, p.schemaFields...)
+}
+
+func defaultInt(v int) string {
+	if v == 0 {
+		return ""
 	}
+	return strconv.Itoa(v)
+}
 
+// PluginServe doesn't return until the plugin is done being executed.
+func PluginServe(p *Plugin) {
 	plugin.Serve(&plugin.ServeConfig{
-		HandshakeConfig: types.HandshakeConfig,
-		Plugins:         pluginMap,
+		HandshakeConfig:  types.HandshakeConfig,
+		VersionedPlugins: types.VersionedPlugins(p),
 	})
 }
 
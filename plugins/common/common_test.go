@@ -1,8 +1,16 @@
 package common
 
 import (
+	"autonity-oracle/config"
+	"autonity-oracle/types"
+	"fmt"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/require"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestConvertSymbol(t *testing.T) {
@@ -32,3 +40,270 @@ func TestResolveSeparator(t *testing.T) {
 	symbol = "BTCUSD"
 	require.Equal(t, "", ResolveSeparator(symbol))
 }
+
+func TestSymbolMetadata(t *testing.T) {
+	p := &Plugin{conf: &config.PluginConfig{DataUpdateInterval: 30}}
+	metadata := p.symbolMetadata([]string{"EUR/USD", "NTN/ATN", "NTN/USDC", "BTCUSD"})
+	require.Equal(t, []types.SymbolMetadata{
+		{Symbol: "EUR-USD", QuoteCurrency: "USD", UpdateInterval: 30, MarketHours: types.MarketWeekdays},
+		{Symbol: "NTN-ATN", QuoteCurrency: "ATN", UpdateInterval: 30, Derived: true},
+		{Symbol: "NTN-USDC", QuoteCurrency: "USDC", UpdateInterval: 30},
+		{Symbol: "BTCUSD", UpdateInterval: 30},
+	}, metadata)
+}
+
+func TestLoadPluginConf(t *testing.T) {
+	t.Run("test load conf from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "conf.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"name":"forex_test","key":"secret"}`), 0600))
+		t.Setenv(types.PluginConfFileEnv, path)
+		t.Setenv("forex_test", `{"name":"forex_test","key":"legacy"}`)
+
+		conf, err := LoadPluginConf("./plugins/forex_test")
+		require.NoError(t, err)
+		require.Equal(t, "secret", conf.Key)
+	})
+
+	t.Run("test load legacy conf from env", func(t *testing.T) {
+		t.Setenv("forex_test", `{"name":"forex_test","key":"legacy"}`)
+
+		conf, err := LoadPluginConf("./plugins/forex_test")
+		require.NoError(t, err)
+		require.Equal(t, "legacy", conf.Key)
+	})
+}
+
+type historicalClient struct {
+	DataSourceClient
+}
+
+func (c *historicalClient) AvailableSymbols() ([]string, error) {
+	return []string{"USDC/USD"}, nil
+}
+
+func (c *historicalClient) KeyRequired() bool {
+	return false
+}
+
+func (c *historicalClient) FetchPriceAt(symbols []string, _ int64) (Prices, error) {
+	return Prices{{Symbol: symbols[0], Price: "0.9999", Volume: "1"}}, nil
+}
+
+func TestFetchPriceAt(t *testing.T) {
+	p := NewPlugin(&config.PluginConfig{Name: "test"}, &historicalClient{}, "v0.0.1", types.SrcCEX, nil)
+	state, err := p.State(0)
+	require.NoError(t, err)
+	require.True(t, state.Historical)
+
+	report, err := p.FetchPriceAt([]string{"USDC-USD", "EUR-USD"}, 1700000000)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EUR-USD"}, report.UnRecognizableSymbols)
+	require.Equal(t, 1, len(report.Prices))
+	require.Equal(t, "USDC-USD", report.Prices[0].Symbol)
+	require.Equal(t, int64(1700000000), report.Prices[0].Timestamp)
+	// the historical prices are not cached.
+	require.Equal(t, 0, len(p.cachePrices))
+}
+
+func TestRegisterAdapter(t *testing.T) {
+	defConf := &config.PluginConfig{Name: "registry_test", Scheme: "https", Endpoint: "example.com", Timeout: 10}
+	RegisterAdapter("registry_test", defConf, func(conf *config.PluginConfig) (*Plugin, error) {
+		return NewPlugin(conf, &historicalClient{}, "v0.0.1", types.SrcCEX, nil), nil
+	})
+	require.True(t, IsRegistered("registry_test"))
+	require.Contains(t, RegisteredAdapters(), "registry_test")
+	require.Panics(t, func() {
+		RegisterAdapter("registry_test", defConf, nil)
+	})
+
+	// the omitted fields are taken from the default conf.
+	p, err := NewInProcessAdapter("registry_test", &config.PluginConfig{Name: "registry_test", Timeout: 5})
+	require.NoError(t, err)
+	require.Equal(t, "example.com", p.conf.Endpoint)
+	require.Equal(t, 5, p.conf.Timeout)
+	// the metrics of the oracle server process are not forwarded by the in-process plugins.
+	require.False(t, p.processMetrics)
+
+	_, err = NewInProcessAdapter("unknown", &config.PluginConfig{})
+	require.Error(t, err)
+}
+
+func TestConfigSchema(t *testing.T) {
+	defConf := &config.PluginConfig{Name: "test", Scheme: "https", Endpoint: "example.com", Timeout: 10}
+	p := NewPlugin(defConf, &historicalClient{}, "v0.0.1", types.SrcCEX, nil)
+	state, err := p.State(0)
+	require.NoError(t, err)
+	require.Empty(t, state.ConfigSchema)
+
+	p.PublishConfigSchema(defConf, types.ConfigField{Name: "swapAddress", Type: types.ConfigAddress, Required: true})
+	state, err = p.State(0)
+	require.NoError(t, err)
+	require.Equal(t, 7, len(state.ConfigSchema))
+	require.Equal(t, "key", state.ConfigSchema[0].Name)
+	require.True(t, state.ConfigSchema[0].Secret)
+	require.Equal(t, "10", state.ConfigSchema[3].Default)
+	require.Equal(t, "", state.ConfigSchema[4].Default)
+	require.Equal(t, "symbolMap", state.ConfigSchema[5].Name)
+	require.Equal(t, "swapAddress", state.ConfigSchema[6].Name)
+
+	// the swap address is required without a default.
+	_, err = defConf.CheckSchema(state.ConfigSchema)
+	require.ErrorIs(t, err, types.ErrInvalidPluginConfig)
+	_, err = (&config.PluginConfig{Name: "test", SwapAddress: "0x218F76e357594C82Cc29A88B90dd67b180827c88"}).CheckSchema(state.ConfigSchema)
+	require.NoError(t, err)
+}
+
+type forexClient struct {
+	historicalClient
+}
+
+func (c *forexClient) AvailableSymbols() ([]string, error) {
+	return []string{"EURUSD", "USDJPY", "GBPUSD"}, nil
+}
+
+func (c *forexClient) FetchPrice(symbols []string) (Prices, error) {
+	prices := map[string]string{"EURUSD": "1.1", "USDJPY": "160", "GBPUSD": "1.25"}
+	var res Prices
+	for _, s := range symbols {
+		res = append(res, Price{Symbol: s, Price: prices[s], Volume: "1"})
+	}
+	return res, nil
+}
+
+func TestSymbolMap(t *testing.T) {
+	conf := &config.PluginConfig{Name: "test", DataUpdateInterval: 60, SymbolMap: config.SymbolMap{
+		"EUR-USD": "EURUSD",
+		"JPY-USD": "1/USDJPY",
+	}}
+	p := NewPlugin(conf, &forexClient{}, "v0.0.1", types.SrcCEX, nil)
+	state, err := p.State(0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EUR-USD", "JPY-USD", "GBPUSD"}, state.AvailableSymbols)
+	require.Equal(t, "JPY-USD", state.Symbols[1].Symbol)
+	require.Equal(t, types.MarketWeekdays, state.Symbols[1].MarketHours)
+
+	// the ticker mapped by JPY-USD is not resolved for USD-JPY, the unmapped GBP-USD is resolved by the separator.
+	report, err := p.FetchPrices([]string{"EUR-USD", "JPY-USD", "GBP-USD", "USD-JPY"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"USD-JPY"}, report.UnRecognizableSymbols)
+	require.Equal(t, 3, len(report.Prices))
+	prices := make(map[string]string)
+	for _, pr := range report.Prices {
+		prices[pr.Symbol] = pr.Price.String()
+	}
+	require.Equal(t, map[string]string{"EUR-USD": "1.1", "JPY-USD": "0.00625", "GBP-USD": "1.25"}, prices)
+
+	// the mapped prices are cached by the protocol symbols.
+	report, err = p.FetchPrices([]string{"JPY-USD"})
+	require.NoError(t, err)
+	require.Equal(t, "0.00625", report.Prices[0].Price.String())
+	require.Equal(t, int64(1), p.metrics.Counter(MetricCacheHits))
+}
+
+type limitedClient struct {
+	historicalClient
+	metrics *Metrics
+}
+
+func (c *limitedClient) FetchPrice(_ []string) (Prices, error) {
+	c.metrics.Inc("limited", 1)
+	return nil, ErrAccessLimited
+}
+
+func (c *limitedClient) SetMetrics(m *Metrics) {
+	c.metrics = m
+}
+
+func TestPluginMetrics(t *testing.T) {
+	p := NewPlugin(&config.PluginConfig{Name: "test", DataUpdateInterval: 60}, &historicalClient{}, "v0.0.1", types.SrcCEX, nil)
+	p.cachePrices["USDC-USD"] = types.Price{Symbol: "USDC-USD", Timestamp: time.Now().Unix()}
+	_, err := p.State(0)
+	require.NoError(t, err)
+
+	report, err := p.FetchPrices([]string{"USDC-USD"})
+	require.NoError(t, err)
+	require.Contains(t, report.Metrics, types.Metric{Name: MetricCacheHits, Type: types.MetricCounter, Value: 1})
+	require.Contains(t, report.Metrics, types.Metric{Name: MetricCacheHitRatio, Type: types.MetricGauge, Value: 1})
+
+	client := &limitedClient{}
+	p = NewPlugin(&config.PluginConfig{Name: "test"}, client, "v0.0.1", types.SrcCEX, nil)
+	_, err = p.State(0)
+	require.NoError(t, err)
+	report, err = p.FetchPrices([]string{"USDC-USD"})
+	require.ErrorIs(t, err, ErrAccessLimited)
+	require.Contains(t, report.Metrics, types.Metric{Name: MetricRateLimited, Type: types.MetricCounter, Value: 1})
+	require.Contains(t, report.Metrics, types.Metric{Name: MetricCacheHitRatio, Type: types.MetricGauge, Value: 0})
+	require.Contains(t, report.Metrics, types.Metric{Name: "limited", Type: types.MetricCounter, Value: 1})
+}
+
+type budgetedClient struct {
+	forexClient
+	denied bool
+}
+
+func (c *budgetedClient) FetchPrice(symbols []string) (Prices, error) {
+	if c.denied {
+		return nil, fmt.Errorf("%w: the requests are spaced by 43m0s to last the billing period", ErrBudgetExceeded)
+	}
+	return c.forexClient.FetchPrice(symbols)
+}
+
+func TestBudgetDenial(t *testing.T) {
+	client := &budgetedClient{denied: true}
+	p := NewPlugin(&config.PluginConfig{Name: "test", DataUpdateInterval: 30}, client, "v0.0.1", types.SrcCEX, nil)
+	_, err := p.State(0)
+	require.NoError(t, err)
+
+	// a denial without the cached prices is not a failure of the fetch.
+	report, err := p.FetchPrices([]string{"EUR-USD"})
+	require.NoError(t, err)
+	require.Empty(t, report.Prices)
+
+	client.denied = false
+	report, err = p.FetchPrices([]string{"EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(report.Prices))
+
+	// the expired prices are served with their original timestamps once the budget denies the requests.
+	ts := time.Now().Unix() - 600
+	for k, pr := range p.cachePrices {
+		pr.Timestamp = ts
+		p.cachePrices[k] = pr
+	}
+	client.denied = true
+	report, err = p.FetchPrices([]string{"EUR-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(report.Prices))
+	require.Equal(t, ts, report.Prices[0].Timestamp)
+	require.Equal(t, "1.1", report.Prices[0].Price.String())
+	require.Equal(t, int64(2), p.metrics.Counter(MetricRateLimited))
+	require.Equal(t, int64(0), p.metrics.Counter(MetricFetchErrors))
+}
+
+func TestObserveResponse(t *testing.T) {
+	before := ProcessMetrics.Counter(MetricHTTPStatus + "429")
+	_, err := observeResponse(&http.Response{StatusCode: http.StatusTooManyRequests}, nil)
+	require.NoError(t, err)
+	require.Equal(t, before+1, ProcessMetrics.Counter(MetricHTTPStatus+"429"))
+
+	before = ProcessMetrics.Counter(MetricHTTPErrors)
+	_, err = observeResponse(nil, ErrDataNotAvailable)
+	require.Error(t, err)
+	require.Equal(t, before+1, ProcessMetrics.Counter(MetricHTTPErrors))
+}
+
+func TestChangedPrices(t *testing.T) {
+	now := time.Now()
+	ntn := types.Price{Timestamp: 1, Symbol: "NTN-USD", Price: decimal.RequireFromString("1.5")}
+	atn := types.Price{Timestamp: 1, Symbol: "ATN-USD", Price: decimal.RequireFromString("2.5")}
+	pushed := map[string]pushedPrice{"NTN-USD": {price: ntn, at: now}}
+
+	// the unchanged price is skipped until the refresh interval.
+	require.Equal(t, []types.Price{atn}, changedPrices([]types.Price{ntn, atn}, pushed, now))
+	require.Equal(t, []types.Price{ntn}, changedPrices([]types.Price{ntn}, pushed, now.Add(streamRefresh)))
+
+	updated := ntn
+	updated.Price = decimal.RequireFromString("1.6")
+	require.Equal(t, []types.Price{updated}, changedPrices([]types.Price{updated}, pushed, now))
+	require.Empty(t, changedPrices(nil, pushed, now))
+}
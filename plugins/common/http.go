@@ -0,0 +1,264 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryBackoff = 500 * time.Millisecond
+	maxRetryBackoff     = 10 * time.Second
+)
+
+var (
+	ErrResponseTooLarge = errors.New("the response of data source exceeds the size limit")
+
+	jitterLock sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano())) //nolint
+)
+
+// newHTTPConnection creates the connection with the HTTP client settings of a plugin, an invalid setting, for example
+// a missing CA bundle, fails the requests with its error.
+func newHTTPConnection(duration time.Duration, host string, conf config.HTTPConfig) *connection {
+	conn := &connection{
+		client:          &http.Client{Timeout: duration},
+		host:            host,
+		timeout:         duration,
+		retries:         conf.Retries,
+		backoff:         time.Duration(conf.RetryBackoff) * time.Millisecond,
+		headers:         conf.Headers,
+		maxResponseSize: conf.MaxResponseSize,
+	}
+	if conn.backoff == 0 {
+		conn.backoff = defaultRetryBackoff
+	}
+
+	transport, err := newTransport(conf)
+	if err != nil {
+		conn.err = fmt.Errorf("invalid http settings: %w", err)
+		return conn
+	}
+	conn.client.Transport = transport
+	return conn
+}
+
+func newTransport(conf config.HTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if conf.Proxy != "" {
+		proxy, err := url.Parse(conf.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	if conf.CAFile == "" && conf.CertFile == "" {
+		return transport, nil
+	}
+
+	tlsConf := &tls.Config{MinVersion: tls.VersionTLS12}
+	if conf.CAFile != "" {
+		bundle, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificate is found in the CA bundle %s", conf.CAFile)
+		}
+		tlsConf.RootCAs = pool
+	}
+	if conf.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConf.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConf
+	return transport, nil
+}
+
+// do sends the request with the extra headers, it retries the request failed with a 5xx status or a timeout with the
+// jittered backoff, the 4xx statuses are not retried as they are not going to be resolved by a retry. The request with
+// its retries is bounded by the timeout of the plugin, each attempt takes an equal share of it thus a hanging attempt
+// leaves the time for its retries, and it is not retried if the backoff exceeds the remaining time. Each attempt takes
+// a request from the budget if there is one, as each of them counts in the quota of the provider.
+func (conn *connection) do(req *http.Request) (*http.Response, error) {
+	if conn.err != nil {
+		return nil, conn.err
+	}
+
+	for k, v := range conn.headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	cancel := context.CancelFunc(func() {})
+	if conn.timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), conn.timeout)
+		req = req.WithContext(ctx)
+	}
+
+	res, cancelAttempt, err := conn.attempts(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the contexts are cancelled once the body is closed, as the body is read after the return.
+	res.Body = &cancelBody{ReadCloser: res.Body, cancel: func() {
+		cancelAttempt()
+		cancel()
+	}}
+	return conn.limitResponse(res)
+}
+
+// attemptTimeout is the share of the request timeout taken by each attempt.
+func (conn *connection) attemptTimeout() time.Duration {
+	if conn.timeout <= 0 {
+		return 0
+	}
+	return conn.timeout / time.Duration(conn.retries+1)
+}
+
+// attempt sends the request once within the attempt timeout, the returned cancel function releases the context of
+// the attempt once its response is done.
+func (conn *connection) attempt(req *http.Request) (*http.Response, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+	if timeout := conn.attemptTimeout(); timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), timeout)
+		req = req.WithContext(ctx)
+	}
+
+	res, err := observeResponse(conn.client.Do(req))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return res, cancel, nil
+}
+
+func (conn *connection) attempts(req *http.Request) (*http.Response, context.CancelFunc, error) {
+	// a request with a body is retried only if the body can be rewound.
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	for attempt := 0; ; attempt++ {
+		if conn.budget != nil {
+			if err := conn.budget.Acquire(); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		res, cancel, err := conn.attempt(req)
+		if err == nil && conn.budget != nil {
+			conn.budget.Observe(res)
+		}
+		if attempt >= conn.retries || !replayable || !retryable(res, err) {
+			return res, cancel, err
+		}
+
+		backoff := jitteredBackoff(conn.backoff, attempt)
+		if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < backoff {
+			return res, cancel, err
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, io.LimitReader(res.Body, 4096)) //nolint
+			res.Body.Close()
+			cancel()
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-req.Context().Done():
+			return nil, nil, req.Context().Err()
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// cancelBody cancels the context of the request once the response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+func retryable(res *http.Response, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr) && netErr.Timeout()
+	}
+	return res.StatusCode >= http.StatusInternalServerError
+}
+
+// jitteredBackoff doubles the backoff on each retry, and it takes a random duration from the upper half of it.
+func jitteredBackoff(base time.Duration, attempt int) time.Duration {
+	d := base << attempt
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	jitterLock.Lock()
+	defer jitterLock.Unlock()
+	return d/2 + time.Duration(jitterRand.Int63n(int64(d/2)+1))
+}
+
+func (conn *connection) limitResponse(res *http.Response) (*http.Response, error) {
+	if conn.maxResponseSize <= 0 {
+		return res, nil
+	}
+	if res.ContentLength > conn.maxResponseSize {
+		res.Body.Close()
+		return nil, ErrResponseTooLarge
+	}
+	res.Body = &limitedBody{ReadCloser: res.Body, remaining: conn.maxResponseSize}
+	return res, nil
+}
+
+// limitedBody fails the reading of a response body once it exceeds the size limit.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.remaining {
+		n = int(b.remaining)
+		b.remaining = 0
+		return n, ErrResponseTooLarge
+	}
+	b.remaining -= int64(n)
+	return n, err
+}
@@ -0,0 +1,176 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPConnection(t *testing.T) {
+	t.Run("test retries on 5xx but not on 4xx", func(t *testing.T) {
+		var hits int
+		status := []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "oracle", r.Header.Get("X-Client"))
+			w.WriteHeader(status[hits%len(status)])
+			hits++
+		}))
+		defer server.Close()
+
+		conn := newHTTPConnection(time.Second, strings.TrimPrefix(server.URL, "http://"),
+			config.HTTPConfig{Retries: 2, RetryBackoff: 1, Headers: map[string]string{"X-Client": "oracle"}})
+		res, err := conn.Request("http", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, 3, hits)
+
+		status = []int{http.StatusNotFound}
+		hits = 0
+		res, err = conn.Request("http", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusNotFound, res.StatusCode)
+		require.Equal(t, 1, hits)
+	})
+
+	t.Run("test retries are bounded by the timeout", func(t *testing.T) {
+		var hits int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		// the backoff of the first retry exceeds the timeout, thus the failed response is returned without retrying.
+		conn := newHTTPConnection(time.Second, strings.TrimPrefix(server.URL, "http://"),
+			config.HTTPConfig{Retries: 3, RetryBackoff: 5000})
+		start := time.Now()
+		res, err := conn.Request("http", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+		require.Equal(t, 1, hits)
+		require.Less(t, time.Since(start), time.Second)
+
+		// the request hanging on the data source is bounded by the timeout with its retries.
+		release := make(chan struct{})
+		defer close(release)
+		hang := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}))
+		defer hang.Close()
+		conn = newHTTPConnection(200*time.Millisecond, strings.TrimPrefix(hang.URL, "http://"),
+			config.HTTPConfig{Retries: 3, RetryBackoff: 1})
+		start = time.Now()
+		_, err = conn.Request("http", &url.URL{Path: "/"})
+		require.Error(t, err)
+		require.Less(t, time.Since(start), 400*time.Millisecond)
+	})
+
+	t.Run("test timeout is retried", func(t *testing.T) {
+		var hits atomic.Int32
+		release := make(chan struct{})
+		defer close(release)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the first attempt hangs until its share of the timeout is over, the retry succeeds.
+			if hits.Add(1) == 1 {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+				return
+			}
+			w.Write([]byte("ok")) //nolint
+		}))
+		defer server.Close()
+
+		conn := newHTTPConnection(time.Second, strings.TrimPrefix(server.URL, "http://"),
+			config.HTTPConfig{Retries: 1, RetryBackoff: 1})
+		res, err := conn.Request("http", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, "ok", string(body))
+		require.Equal(t, int32(2), hits.Load())
+	})
+
+	t.Run("test response size limit", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(strings.Repeat("a", 100))) //nolint
+		}))
+		defer server.Close()
+
+		conn := newHTTPConnection(time.Second, strings.TrimPrefix(server.URL, "http://"), config.HTTPConfig{MaxResponseSize: 10})
+		_, err := conn.Request("http", &url.URL{Path: "/"})
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+
+		conn = newHTTPConnection(time.Second, strings.TrimPrefix(server.URL, "http://"), config.HTTPConfig{MaxResponseSize: 100})
+		res, err := conn.Request("http", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		require.Equal(t, 100, len(body))
+		res.Body.Close()
+	})
+
+	t.Run("test proxy", func(t *testing.T) {
+		var proxied string
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied = r.URL.String()
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer proxy.Close()
+
+		conn := newHTTPConnection(time.Second, "example.com", config.HTTPConfig{Proxy: proxy.URL})
+		res, err := conn.Request("http", &url.URL{Path: "/api"})
+		require.NoError(t, err)
+		res.Body.Close()
+		require.Equal(t, "http://example.com/api", proxied)
+	})
+
+	t.Run("test custom CA bundle", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		host := strings.TrimPrefix(server.URL, "https://")
+
+		_, err := newHTTPConnection(time.Second, host, config.HTTPConfig{}).Request("https", &url.URL{Path: "/"})
+		require.Error(t, err)
+
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		require.NoError(t, os.WriteFile(caFile, ca, 0600))
+		res, err := newHTTPConnection(time.Second, host, config.HTTPConfig{CAFile: caFile}).Request("https", &url.URL{Path: "/"})
+		require.NoError(t, err)
+		res.Body.Close()
+
+		// the invalid settings fail the requests.
+		_, err = newHTTPConnection(time.Second, host, config.HTTPConfig{CAFile: "./missing.pem"}).Request("https", &url.URL{Path: "/"})
+		require.ErrorContains(t, err, "invalid http settings")
+	})
+}
+
+func TestJitteredBackoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		d := jitteredBackoff(time.Second, attempt)
+		require.LessOrEqual(t, d, maxRetryBackoff)
+		require.GreaterOrEqual(t, d, time.Second/2)
+	}
+	require.GreaterOrEqual(t, jitteredBackoff(time.Second, 2), 2*time.Second)
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"autonity-oracle/types"
+	"sort"
+	"sync"
+)
+
+// ProcessMetrics collects the metrics shared by the plugin process, for example the status codes of the HTTP
+// connections. They are forwarded by the plugin binaries only, as the in-process plugins share the oracle server process.
+var ProcessMetrics = NewMetrics()
+
+// Metrics collects the plugin-side counters and gauges, they are carried by the price reports to the oracle server,
+// and registered under the plugin's namespace. The counters are cumulative since the plugin starts.
+type Metrics struct {
+	lock     sync.Mutex
+	counters map[string]int64
+	gauges   map[string]float64
+}
+
+// MetricsRecorder is the optional capability of a DataSourceClient which records its own metrics, for example the swap
+// events of an AMM. The metrics of the plugin are handed to the client once the plugin is created.
+type MetricsRecorder interface {
+	SetMetrics(m *Metrics)
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{counters: make(map[string]int64), gauges: make(map[string]float64)}
+}
+
+// Inc increases the counter by n.
+func (m *Metrics) Inc(name string, n int64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.counters[name] += n
+}
+
+// Update sets the value of the gauge.
+func (m *Metrics) Update(name string, v float64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.gauges[name] = v
+}
+
+// Counter returns the count of the counter.
+func (m *Metrics) Counter(name string) int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.counters[name]
+}
+
+// Snapshot returns the metrics sorted by their names.
+func (m *Metrics) Snapshot() []types.Metric {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	snapshot := make([]types.Metric, 0, len(m.counters)+len(m.gauges))
+	for name, v := range m.counters {
+		snapshot = append(snapshot, types.Metric{Name: name, Type: types.MetricCounter, Value: float64(v)})
+	}
+	for name, v := range m.gauges {
+		snapshot = append(snapshot, types.Metric{Name: name, Type: types.MetricGauge, Value: v})
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Name < snapshot[j].Name
+	})
+	return snapshot
+}
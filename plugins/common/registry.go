@@ -0,0 +1,74 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// AdapterFactory creates the adapter of a plugin from its resolved configuration, it is called by the oracle server to
+// run the plugin in its own process rather than launching the plugin binary.
+type AdapterFactory func(conf *config.PluginConfig) (*Plugin, error)
+
+type registration struct {
+	defConf config.PluginConfig
+	factory AdapterFactory
+}
+
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[string]registration)
+)
+
+// RegisterAdapter registers the adapter factory of a plugin by its name, it is called from the init() of the plugin's
+// package, thus the plugin is linked into the oracle server at compile time. It panics on a duplicated name.
+func RegisterAdapter(name string, defConf *config.PluginConfig, factory AdapterFactory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	if _, ok := registry[name]; ok {
+		panic(fmt.Sprintf("plugin %s is registered twice", name))
+	}
+	registry[name] = registration{defConf: *defConf, factory: factory}
+}
+
+// IsRegistered returns true if the plugin is registered to run in process.
+func IsRegistered(name string) bool {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	_, ok := registry[name]
+	return ok
+}
+
+// RegisteredAdapters returns the names of the registered plugins in order.
+func RegisteredAdapters() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewInProcessAdapter creates the adapter of a registered plugin, the omitted fields of the conf are filled with the
+// default conf of the plugin as it is done by ResolveConf for the plugin binaries.
+func NewInProcessAdapter(name string, conf *config.PluginConfig) (*Plugin, error) {
+	registryLock.RLock()
+	r, ok := registry[name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("plugin %s is not registered to run in process", name)
+	}
+
+	c := *conf
+	applyDefaultConf(&c, &r.defConf)
+	p, err := r.factory(&c)
+	if err != nil {
+		return nil, err
+	}
+	// the metrics of the process are the oracle server's, thus they are not forwarded by the in-process plugins.
+	p.processMetrics = false
+	return p, nil
+}
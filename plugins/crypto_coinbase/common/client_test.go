@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"github.com/shopspring/decimal"
@@ -7,7 +7,7 @@ import (
 )
 
 func TestNewCoinBaseClient(t *testing.T) {
-	client := NewCoinBaseClient(&defaultConfig)
+	client := NewCoinBaseClient(&DefaultConfig)
 	defer client.Close()
 	prices, err := client.FetchPrice([]string{"USDC-USD"})
 	require.NoError(t, err)
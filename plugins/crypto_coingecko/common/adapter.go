@@ -0,0 +1,18 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+)
+
+func init() {
+	common.RegisterAdapter(DefaultConfig.Name, &DefaultConfig, NewAdapter)
+}
+
+// NewAdapter creates the crypto_coingecko adapter, it is shared by the plugin binary and the in-process plugin.
+func NewAdapter(conf *config.PluginConfig) (*common.Plugin, error) {
+	adapter := common.NewPlugin(conf, NewCoinGeckoClient(conf), Version, types.SrcCEX, nil)
+	adapter.PublishConfigSchema(&DefaultConfig, common.ClientConfigFields()...)
+	return adapter, nil
+}
@@ -0,0 +1,225 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+)
+
+const (
+	Version         = "v0.2.0"
+	path            = "0/public/Ticker"
+	ohlcPath        = "0/public/OHLC"
+	queryParam      = "pair"
+	supportedSymbol = "USDCUSD"
+	candleInterval  = 60 // the interval in seconds of the OHLC candles.
+)
+
+var DefaultConfig = config.PluginConfig{
+	Name:               "crypto_kraken",
+	Key:                "",
+	Scheme:             "https",
+	Endpoint:           "api.kraken.com",
+	Timeout:            10, // 10s
+	DataUpdateInterval: 30, // 30s, tested and passed the rate limit policy of public data service of kraken.
+}
+
+type Result struct {
+	A []string `json:"a"` // ask price [price, whole lot volume, lot volume]
+	B []string `json:"b"` // bid price [price, whole lot volume, lot volume]
+	C []string `json:"c"` // last trade closed [price, lot volume]
+	V []string `json:"v"` // volume [today, last 24 hours]
+	P []string `json:"p"` // volume weighted average price [today, last 24 hours]
+	T []int64  `json:"t"` // num of trades [today, last 24 hours]
+	L []string `json:"l"` // low [today, last 24 hours]
+	H []string `json:"h"` // high [today, last 24 hours]
+	O string   `json:"o"` // today's opening price
+}
+
+type Response struct {
+	Error  []string          `json:"error"`
+	Result map[string]Result `json:"result"`
+}
+
+// OHLCResponse is the response of the OHLC candles, the result carries the candles of the pair, and the "last" ID.
+type OHLCResponse struct {
+	Error  []string                   `json:"error"`
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+type KrakenClient struct {
+	conf   *config.PluginConfig
+	client *common.Client
+	logger hclog.Logger
+}
+
+func NewKrakenClient(conf *config.PluginConfig) *KrakenClient {
+	client := common.NewPluginClient(conf)
+	logger := hclog.New(&hclog.LoggerOptions{
+		Name:   conf.Name,
+		Level:  hclog.Info,
+		Output: os.Stdout,
+	})
+
+	return &KrakenClient{conf: conf, client: client, logger: logger}
+}
+
+func (k *KrakenClient) KeyRequired() bool {
+	return false
+}
+
+func (k *KrakenClient) FetchPrice(_ []string) (common.Prices, error) {
+	var prices common.Prices
+	u := k.buildURL()
+	res, err := k.client.Conn.Request(k.conf.Scheme, u)
+	if err != nil {
+		k.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		k.logger.Error("data source return error", "error", err.Error())
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		k.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result Response
+	err = json.Unmarshal(body, &result)
+	if err != nil {
+		k.logger.Error("unmarshal result", "error", err.Error())
+		return nil, err
+	}
+
+	if len(result.Error) > 0 {
+		k.logger.Error("data source return error", "error", result.Error)
+		return nil, fmt.Errorf("%s", result.Error[0])
+	}
+
+	p, err := k.toPrice(common.DefaultUSDCSymbol, &result)
+	if err != nil {
+		k.logger.Error("error filling USDC-USD price data", "err", err.Error())
+		return nil, err
+	}
+
+	prices = append(prices, p)
+	return prices, nil
+}
+
+// FetchPriceAt fetches the price at the timestamp from the 1-minute OHLC candles, it implements the
+// common.HistoricalClient.
+func (k *KrakenClient) FetchPriceAt(_ []string, ts int64) (common.Prices, error) {
+	u := &url.URL{Path: ohlcPath}
+	query := u.Query()
+	query.Set(queryParam, supportedSymbol)
+	query.Set("interval", "1")
+	query.Set("since", strconv.FormatInt(ts-candleInterval, 10))
+	u.RawQuery = query.Encode()
+
+	res, err := k.client.Conn.Request(k.conf.Scheme, u)
+	if err != nil {
+		k.logger.Error("https request", "error", err.Error())
+		return nil, err
+	}
+	defer res.Body.Close()
+	if err = common.CheckHTTPStatusCode(res.StatusCode); err != nil {
+		k.logger.Error("data source return error", "error", err.Error())
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		k.logger.Error("io read", "error", err.Error())
+		return nil, err
+	}
+
+	var result OHLCResponse
+	if err = json.Unmarshal(body, &result); err != nil {
+		k.logger.Error("unmarshal result", "error", err.Error())
+		return nil, err
+	}
+
+	if len(result.Error) > 0 {
+		k.logger.Error("data source return error", "error", result.Error)
+		return nil, fmt.Errorf("%s", result.Error[0])
+	}
+
+	price, err := candleAt(result.Result[supportedSymbol], ts)
+	if err != nil {
+		return nil, err
+	}
+
+	return common.Prices{{Symbol: common.DefaultUSDCSymbol, Price: price, Volume: types.DefaultVolume.String()}}, nil
+}
+
+// candleAt returns the price of the candle that covers the timestamp, the candle is in the format of
+// [time, open, high, low, close, vwap, volume, count]. The vwap is taken, or the close if there is no trade in it.
+func candleAt(raw json.RawMessage, ts int64) (string, error) {
+	var candles [][]interface{}
+	if err := json.Unmarshal(raw, &candles); err != nil {
+		return "", err
+	}
+
+	for _, c := range candles {
+		if len(c) < 7 {
+			continue
+		}
+		start, ok := c[0].(float64)
+		if !ok || ts < int64(start) || ts >= int64(start)+candleInterval {
+			continue
+		}
+
+		closePrice, _ := c[4].(string)
+		vwap, _ := c[5].(string)
+		volume, _ := c[6].(string)
+		if v, err := strconv.ParseFloat(volume, 64); err != nil || v == 0 {
+			return closePrice, nil
+		}
+		return vwap, nil
+	}
+	return "", fmt.Errorf("no candle found at %d", ts)
+}
+
+func (k *KrakenClient) toPrice(symbol string, res *Response) (common.Price, error) {
+	var price common.Price
+
+	usdcResult, ok := res.Result[supportedSymbol]
+	if !ok {
+		return price, fmt.Errorf("symbol %s not found", symbol)
+	}
+
+	if len(usdcResult.P) == 0 {
+		return price, fmt.Errorf("%s price not found", symbol)
+	}
+
+	price.Symbol = symbol
+	price.Price = usdcResult.P[0] // take the volume weighted average price of today.
+	price.Volume = types.DefaultVolume.String()
+	return price, nil
+}
+
+func (k *KrakenClient) AvailableSymbols() ([]string, error) {
+	return []string{common.DefaultUSDCSymbol}, nil
+}
+
+func (k *KrakenClient) Close() {
+	k.client.Conn.Close()
+}
+
+func (k *KrakenClient) buildURL() *url.URL {
+	endpoint := &url.URL{}
+	endpoint.Path = path
+	query := endpoint.Query()
+	query.Set(queryParam, supportedSymbol)
+	endpoint.RawQuery = query.Encode()
+	return endpoint
+}
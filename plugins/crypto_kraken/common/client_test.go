@@ -0,0 +1,35 @@
+package common
+
+import (
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestNewKrakenClient(t *testing.T) {
+	client := NewKrakenClient(&DefaultConfig)
+	defer client.Close()
+	prices, err := client.FetchPrice([]string{"USDC-USD"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, "USDC-USD", prices[0].Symbol)
+	_, err = decimal.NewFromString(prices[0].Price)
+	require.NoError(t, err)
+}
+
+func TestCandleAt(t *testing.T) {
+	raw := []byte(`[[1700000000,"0.9998","1.0001","0.9997","0.9999","0.99985","1200.5",12],
+		[1700000060,"0.9999","0.9999","0.9999","0.9999","0.0000","0.00000000",0]]`)
+
+	price, err := candleAt(raw, 1700000030)
+	require.NoError(t, err)
+	require.Equal(t, "0.99985", price)
+
+	// the close price is taken for the candle without trades.
+	price, err = candleAt(raw, 1700000060)
+	require.NoError(t, err)
+	require.Equal(t, "0.9999", price)
+
+	_, err = candleAt(raw, 1700000120)
+	require.Error(t, err)
+}
@@ -0,0 +1,48 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+)
+
+// DefaultConfig is the config for the ATN-USDCx, NTN-USDCx, NTN-ATN market place in Piccadilly network.
+var DefaultConfig = config.PluginConfig{
+	Name:               "crypto_uniswap",
+	Scheme:             "wss",                                        // both http/s ws/s works for this plugin
+	Endpoint:           "rpc-internal-1.piccadilly.autonity.org/ws",  // default websocket endpoint for piccadilly network.
+	Timeout:            10,                                           // 10s
+	DataUpdateInterval: common.DefaultAMMDataUpdateInterval,          // 1s, shorten the default data point refresh interval for AMM market data, as they can move very fast.
+	NTNTokenAddress:    types.AutonityContractAddress.Hex(),          // Same as 0xBd770416a3345F91E4B34576cb804a576fa48EB1, Autonity contract address.
+	ATNTokenAddress:    "0xcE17e51cE4F0417A1aB31a3c5d6831ff3BbFa1d2", // Wrapped ATN ERC20 contract address on the target blockchain.
+	USDCTokenAddress:   "0xB855D5e83363A4494e09f0Bb3152A70d3f161940", // USDCx ERC20 contract address on the target blockchain.
+	SwapAddress:        "0x218F76e357594C82Cc29A88B90dd67b180827c88", // UniSwap factory contract address on the target blockchain.
+}
+
+func init() {
+	common.RegisterAdapter(DefaultConfig.Name, &DefaultConfig, NewAdapter)
+}
+
+// NewAdapter creates the uniswap adapter, it is shared by the plugin binary and the in-process plugin.
+func NewAdapter(conf *config.PluginConfig) (*common.Plugin, error) {
+	c, err := NewUniswapClient(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	adapter := common.NewPlugin(conf, c, Version, types.SrcAMM, common.ChainIDPiccadilly)
+	// start the uniswapV2 event watching for price aggregation of ATN-USDCx & NTN-USDCx, once the metrics are set.
+	go c.StartWatcher()
+
+	adapter.PublishConfigSchema(&DefaultConfig,
+		types.ConfigField{Name: "ntnTokenAddress", Type: types.ConfigAddress, Required: true, Default: DefaultConfig.NTNTokenAddress,
+			Description: "the NTN ERC20 token address"},
+		types.ConfigField{Name: "atnTokenAddress", Type: types.ConfigAddress, Required: true, Default: DefaultConfig.ATNTokenAddress,
+			Description: "the Wrapped ATN ERC20 token address"},
+		types.ConfigField{Name: "usdcTokenAddress", Type: types.ConfigAddress, Required: true, Default: DefaultConfig.USDCTokenAddress,
+			Description: "the USDCx ERC20 token address"},
+		types.ConfigField{Name: "swapAddress", Type: types.ConfigAddress, Required: true, Default: DefaultConfig.SwapAddress,
+			Description: "the UniSwap factory contract address"},
+	)
+	return adapter, nil
+}
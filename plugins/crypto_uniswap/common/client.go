@@ -28,6 +28,9 @@ var (
 	NTNUSDC           = "NTN-USDC"
 	supportedSymbols  = common.DefaultCryptoSymbols
 	NTNTokenAddress   = types.AutonityContractAddress // Autonity protocol contract is the NTN token contract.
+
+	metricATNSwapEvents = "swap_events/atn_usdc"
+	metricNTNSwapEvents = "swap_events/ntn_usdc"
 )
 
 type Order struct {
@@ -70,6 +73,9 @@ type UniswapClient struct {
 
 	priceMutex           sync.RWMutex
 	lastAggregatedPrices map[ecommon.Address]common.Price
+	chPriceUpdates       chan struct{}
+
+	metrics *common.Metrics // the plugin-side metrics, it is set by the plugin before the watcher starts.
 }
 
 func NewUniswapClient(conf *config.PluginConfig) (*UniswapClient, error) {
@@ -118,6 +124,8 @@ func NewUniswapClient(conf *config.PluginConfig) (*UniswapClient, error) {
 		doneCh:               make(chan struct{}),
 		ticker:               time.NewTicker(time.Second * 30),
 		lastAggregatedPrices: make(map[ecommon.Address]common.Price),
+		chPriceUpdates:       make(chan struct{}, 1),
+		metrics:              common.NewMetrics(),
 	}
 
 	uc.atnOrderBooks.SetCapacity(orderBookCapacity)
@@ -154,6 +162,11 @@ func (e *UniswapClient) EventSubscription() error {
 	return nil
 }
 
+// SetMetrics implements the common.MetricsRecorder, the swap events are counted into the plugin-side metrics.
+func (e *UniswapClient) SetMetrics(m *common.Metrics) {
+	e.metrics = m
+}
+
 func (e *UniswapClient) StartWatcher() {
 	for {
 		select {
@@ -175,12 +188,14 @@ func (e *UniswapClient) StartWatcher() {
 			}
 		case atnSwapEvent := <-e.chAtnSwapEvent:
 			e.logger.Debug("receiving an ATN-USDC swap event", "event", atnSwapEvent)
+			e.metrics.Inc(metricATNSwapEvents, 1)
 
 			if err := e.handleSwapEvent(e.atnTokenAddress, e.atnUSDCPairContract, atnSwapEvent, &e.atnOrderBooks); err != nil {
 				e.logger.Error("handle swap event failed", "error", err)
 			}
 		case ntnSwapEvent := <-e.chNtnSwapEvent:
 			e.logger.Debug("receiving a NTN-USDC swap event", "event", ntnSwapEvent)
+			e.metrics.Inc(metricNTNSwapEvents, 1)
 			if err := e.handleSwapEvent(NTNTokenAddress, e.ntnUSDCPairContract, ntnSwapEvent, &e.ntnOrderBooks); err != nil {
 				e.logger.Error("handle swap event failed", "error", err)
 			}
@@ -329,6 +344,17 @@ func (e *UniswapClient) updatePrice(tokenAddress ecommon.Address, price string,
 		Price:  price,
 		Volume: volumes.String(),
 	}
+
+	// notify the price stream without blocking, pending notifications are coalesced into one.
+	select {
+	case e.chPriceUpdates <- struct{}{}:
+	default:
+	}
+}
+
+// PriceUpdates notifies the aggregated prices are updated by the swap events, thus the plugin streams them.
+func (e *UniswapClient) PriceUpdates() <-chan struct{} {
+	return e.chPriceUpdates
 }
 
 func aggregatePrice(orderBook *ring.Ring, order Order) (decimal.Decimal, *big.Int, error) {
@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"github.com/stretchr/testify/require"
@@ -7,8 +7,8 @@ import (
 
 func TestNewCFClient(t *testing.T) {
 	// this key is only used by testing
-	defaultConfig.Key = "4a1a9ae24658499fb4e8d790f10a0bcd"
-	client := NewCFClient(&defaultConfig)
+	DefaultConfig.Key = "4a1a9ae24658499fb4e8d790f10a0bcd"
+	client := NewCFClient(&DefaultConfig)
 	defer client.Close()
 	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
 	require.NoError(t, err)
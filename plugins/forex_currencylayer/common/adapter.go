@@ -0,0 +1,18 @@
+package common
+
+import (
+	"autonity-oracle/config"
+	"autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+)
+
+func init() {
+	common.RegisterAdapter(DefaultConfig.Name, &DefaultConfig, NewAdapter)
+}
+
+// NewAdapter creates the forex_currencylayer adapter, it is shared by the plugin binary and the in-process plugin.
+func NewAdapter(conf *config.PluginConfig) (*common.Plugin, error) {
+	adapter := common.NewPlugin(conf, NewCLClient(conf), Version, types.SrcCEX, nil)
+	adapter.PublishConfigSchema(&DefaultConfig, common.ClientConfigFields()...)
+	return adapter, nil
+}
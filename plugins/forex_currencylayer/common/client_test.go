@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"github.com/stretchr/testify/require"
@@ -6,8 +6,8 @@ import (
 )
 
 func TestNewCLClient(t *testing.T) {
-	defaultConfig.Key = "c4817087691d124d6ddabbb93411633b"
-	client := NewCLClient(&defaultConfig)
+	DefaultConfig.Key = "c4817087691d124d6ddabbb93411633b"
+	client := NewCLClient(&DefaultConfig)
 	defer client.Close()
 	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
 	require.NoError(t, err)
@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"github.com/stretchr/testify/require"
@@ -7,8 +7,8 @@ import (
 
 func TestNewEXClient(t *testing.T) {
 	// this key is only used by testing
-	defaultConfig.Key = "fc2e53282835eb092f8cafd4"
-	client := NewEXClient(&defaultConfig)
+	DefaultConfig.Key = "fc2e53282835eb092f8cafd4"
+	client := NewEXClient(&DefaultConfig)
 	defer client.Close()
 	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
 	require.NoError(t, err)
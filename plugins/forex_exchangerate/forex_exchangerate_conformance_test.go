@@ -0,0 +1,33 @@
+package main
+
+import (
+	"autonity-oracle/config"
+	common2 "autonity-oracle/plugins/common"
+	client "autonity-oracle/plugins/forex_exchangerate/common"
+	"autonity-oracle/plugintest"
+	"autonity-oracle/plugintest/plugintesting"
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestConformance builds the plugin and runs the conformance test kit against it with the shipped fixtures.
+func TestConformance(t *testing.T) {
+	dir := t.TempDir()
+	out, err := exec.Command("go", "build", "-o", filepath.Join(dir, client.DefaultConfig.Name), ".").CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	fixtures, err := plugintest.LoadFixtures("../../plugintest/fixtures/forex_exchangerate.json")
+	require.NoError(t, err)
+
+	plugintesting.Require(t, &plugintest.Config{
+		PluginDIR:    dir,
+		PluginName:   client.DefaultConfig.Name,
+		PluginConfig: config.PluginConfig{Key: "test-key", Timeout: 2},
+		LoggingLevel: hclog.Warn,
+		ChainID:      common2.ChainIDPiccadilly.Int64(),
+		Fixtures:     fixtures,
+	})
+}
@@ -170,6 +170,7 @@ func (cl *NPClient) buildURL(apiKey string) *url.URL {
 func main() {
 	conf := common.ResolveConf(os.Args[0], &defaultConfig)
 	adapter := common.NewPlugin(conf, NewNPClient(conf), version, types.SrcCEX, nil)
+	adapter.PublishConfigSchema(&defaultConfig)
 	defer adapter.Close()
 	common.PluginServe(adapter)
 }
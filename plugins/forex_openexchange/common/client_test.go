@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"github.com/stretchr/testify/require"
@@ -7,8 +7,8 @@ import (
 
 func TestNewOXClient(t *testing.T) {
 	// this key is only used by testing
-	defaultConfig.Key = "a9482aed38a844e7b08bc29bcaca7985"
-	client := NewOXClient(&defaultConfig)
+	DefaultConfig.Key = "a9482aed38a844e7b08bc29bcaca7985"
+	client := NewOXClient(&DefaultConfig)
 	defer client.Close()
 	prices, err := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
 	require.NoError(t, err)
@@ -1,4 +1,4 @@
-package main
+package common
 
 import (
 	"fmt"
@@ -8,8 +8,8 @@ import (
 
 func TestNewWiseClient(t *testing.T) {
 	// this key is only used by testing
-	defaultConfig.Key = "0x123"
-	client := NewWiseClient(&defaultConfig)
+	DefaultConfig.Key = "0x123"
+	client := NewWiseClient(&DefaultConfig)
 	defer client.Close()
 	prices, _ := client.FetchPrice([]string{"EUR-USD", "JPY-USD", "GBP-USD", "AUD-USD", "CAD-USD", "SEK-USD"})
 	fmt.Print(prices)
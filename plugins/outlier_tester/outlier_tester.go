@@ -189,7 +189,7 @@ type OutlierClient struct {
 }
 
 func NewOutlierClient(conf *config.PluginConfig) *OutlierClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewPluginClient(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Debug,
@@ -20,6 +20,7 @@ var defaultConfig = config.PluginConfig{
 func main() {
 	conf := common.ResolveConf(os.Args[0], &defaultConfig)
 	adapter := common.NewPlugin(conf, client.NewSIMClient(conf), client.Version, types.SrcCEX, common.ChainIDBakerloo)
+	adapter.PublishConfigSchema(&defaultConfig, common.ClientConfigFields()...)
 	defer adapter.Close()
 	common.PluginServe(adapter)
 }
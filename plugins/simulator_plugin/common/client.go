@@ -9,7 +9,6 @@ import (
 	"io"
 	"net/url"
 	"os"
-	"time"
 )
 
 const (
@@ -25,7 +24,7 @@ type SIMClient struct {
 }
 
 func NewSIMClient(conf *config.PluginConfig) *SIMClient {
-	client := common.NewClient(conf.Key, time.Second*time.Duration(conf.Timeout), conf.Endpoint)
+	client := common.NewPluginClient(conf)
 	logger := hclog.New(&hclog.LoggerOptions{
 		Name:   conf.Name,
 		Level:  hclog.Info,
@@ -0,0 +1,111 @@
+package plugintest
+
+import (
+	"autonity-oracle/config"
+	common2 "autonity-oracle/plugins/common"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"os"
+	"strings"
+)
+
+var errMissingPluginName = errors.New("the name of the plugin to be tested is missing")
+
+// ParseConfig resolves the conformance test configuration from the arguments: <name> [flags].
+func ParseConfig(args []string) (*Config, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return nil, errMissingPluginName
+	}
+
+	name := args[0]
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	confFile := fs.String("config", "", "the oracle server config file to load the plugin directory and plugin configs from")
+	pluginDir := fs.String("plugin-dir", "", "the directory of the plugin binary, it overrides the one of the config file")
+	chainID := fs.Int64("chain-id", common2.ChainIDPiccadilly.Int64(), "the chain ID to be accepted by the plugin")
+	symbols := fs.String("symbols", "", "comma separated symbols to be fetched, default to the plugin's available symbols")
+	fixtures := fs.String("fixtures", "", "the JSON file of the stand-in responses keyed by the URL path")
+	logLevel := fs.Int("log-level", int(hclog.Warn), "the logging verbosity of the plugin wrapper")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+
+	conf := &Config{
+		PluginDIR:    config.DefaultConfig.PluginDIR,
+		PluginName:   name,
+		PluginConfig: config.PluginConfig{Name: name},
+		LoggingLevel: hclog.Level(*logLevel), //nolint
+		ChainID:      *chainID,
+	}
+
+	if *confFile != "" {
+		serverConf, err := config.LoadServerConfig(*confFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.PluginDIR = serverConf.PluginDIR
+		for _, c := range serverConf.PluginConfigs {
+			if c.Name == name {
+				conf.PluginConfig = c
+				break
+			}
+		}
+	}
+
+	if *pluginDir != "" {
+		conf.PluginDIR = *pluginDir
+	}
+
+	if *symbols != "" {
+		for _, s := range strings.Split(*symbols, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				conf.Symbols = append(conf.Symbols, s)
+			}
+		}
+	}
+
+	if *fixtures != "" {
+		f, err := LoadFixtures(*fixtures)
+		if err != nil {
+			return nil, err
+		}
+		conf.Fixtures = f
+	}
+
+	return conf, nil
+}
+
+// RunCLI is the entry of the `plugin test` sub command, it prints the results into out and returns the exit code.
+func RunCLI(args []string, out io.Writer) int {
+	conf, err := ParseConfig(args)
+	if err != nil {
+		fmt.Fprintf(out, "cannot parse test arguments: %s\n", err.Error())
+		PrintUsage(out)
+		return 1
+	}
+
+	results, err := Run(conf)
+	if err != nil {
+		fmt.Fprintf(out, "cannot launch plugin %s: %s\n", conf.PluginName, err.Error())
+		return 1
+	}
+
+	for _, r := range results {
+		fmt.Fprintln(out, r.String())
+	}
+	if Failed(results) {
+		fmt.Fprintf(out, "plugin %s does not conform to the adapter contract\n", conf.PluginName)
+		return 1
+	}
+	fmt.Fprintf(out, "plugin %s conforms to the adapter contract\n", conf.PluginName)
+	return 0
+}
+
+func PrintUsage(out io.Writer) {
+	fmt.Fprint(out, "Usage of plugin test:\n")
+	fmt.Fprintf(out, "%s plugin test <name> [-config oracle_config.yml] [-plugin-dir dir] [-chain-id id] "+
+		"[-symbols A-B,C-D] [-fixtures fixtures.json] [-log-level 4]\n", os.Args[0])
+}
@@ -0,0 +1,284 @@
+// Package plugintest is the conformance test kit of the plugins, it launches a plugin binary with the same wrapper used
+// by the oracle server, points the plugin to a local HTTP stand-in of its data source, and runs a standard battery of
+// checks against the types.Adapter contract. It is shared by the plugins in this repository and the external ones,
+// either from their go tests with plugintesting.Require, or from the `plugin test` sub command. The fixtures of the
+// stand-in for the plugins in this repository are shipped in the fixtures directory.
+package plugintest
+
+import (
+	"autonity-oracle/config"
+	pWrapper "autonity-oracle/plugin_wrapper"
+	common2 "autonity-oracle/plugins/common"
+	"autonity-oracle/types"
+	"fmt"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/hashicorp/go-hclog"
+	"strings"
+	"time"
+)
+
+var (
+	unknownSymbol    = "XXX-YYY"             // the symbol which is not provided by any data source.
+	exitPollInterval = 20 * time.Millisecond // the interval to poll the plugin process after a failed call.
+	exitPollTimes    = 10
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Result is the outcome of a check with the details of a failure or a skip.
+type Result struct {
+	Check  string
+	Status Status
+	Detail string
+}
+
+func (r Result) String() string {
+	if r.Detail == "" {
+		return fmt.Sprintf("[%s] %s", r.Status, r.Check)
+	}
+	return fmt.Sprintf("[%s] %s: %s", r.Status, r.Check, r.Detail)
+}
+
+// Config is the configuration of a conformance test run.
+type Config struct {
+	PluginDIR    string
+	PluginName   string
+	PluginConfig config.PluginConfig // the scheme and the endpoint are replaced with the ones of the stand-in.
+	LoggingLevel hclog.Level
+	ChainID      int64
+	Symbols      []string           // the symbols to be fetched, default to the symbols stated by the plugin.
+	Fixtures     map[string]Fixture // the responses of the stand-in by the URL path.
+}
+
+// noopSubscriber satisfies the sample event subscription of the plugin wrapper, the checks drive the fetching.
+type noopSubscriber struct {
+	feed event.Feed
+}
+
+func (s *noopSubscriber) WatchSampleEvent(sink chan<- *types.SampleEvent) event.Subscription {
+	return s.feed.Subscribe(sink)
+}
+
+// suite carries the state shared by the checks of a run.
+type suite struct {
+	conf    *Config
+	pw      *pWrapper.PluginWrapper
+	standIn *StandIn
+	state   types.PluginStatement
+	symbols []string
+}
+
+type check struct {
+	name string
+	run  func(s *suite) (Status, string)
+}
+
+// checks is the standard battery, they run in order, and the state check has to pass for the others to run.
+var checks = []check{
+	{"state", checkState},
+	{"chain id mismatch", checkChainIDMismatch},
+	{"symbols in - form", checkSymbolForm},
+	{"empty input", checkEmptyInput},
+	{"unrecognizable symbols", checkUnrecognizableSymbols},
+	{"prices", checkPrices},
+	{"data source failure", checkDataSourceFailure},
+	{"no panics", checkAlive},
+}
+
+// Run launches the plugin against the stand-in and runs the battery of checks, it returns an error only if the plugin
+// cannot be launched.
+func Run(conf *Config) ([]Result, error) {
+	standIn := NewStandIn(conf.Fixtures)
+	defer standIn.Close()
+
+	pConf := conf.PluginConfig
+	pConf.Name = conf.PluginName
+	pConf.Scheme = "http"
+	pConf.Endpoint = standIn.Host()
+
+	logger := config.NewLogger(&config.DefaultLogConfig, conf.PluginName, conf.LoggingLevel)
+	pw := pWrapper.NewPluginWrapper(logger, conf.PluginName, conf.PluginDIR, &noopSubscriber{}, &pConf, nil)
+	// the statement of the plugin is checked with the chain ID on the launch, thus a plugin rejecting it fails to launch.
+	if err := pw.Initialize(conf.ChainID); err != nil {
+		pw.CleanPluginProcess()
+		return nil, err
+	}
+	defer pw.Close()
+
+	s := &suite{conf: conf, pw: pw, standIn: standIn}
+	var results []Result
+	for _, c := range checks {
+		status, detail := c.run(s)
+		results = append(results, Result{Check: c.name, Status: status, Detail: detail})
+		if c.name == "state" && status == StatusFail {
+			break
+		}
+	}
+	return results, nil
+}
+
+// Failed returns true if any of the results is failed.
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// exited returns true if the plugin process exited, as a panic in the plugin terminates its process.
+func (s *suite) exited() bool {
+	for i := 0; i < exitPollTimes; i++ {
+		if s.pw.Exited() {
+			return true
+		}
+		time.Sleep(exitPollInterval)
+	}
+	return false
+}
+
+// fetch fetches the prices from the plugin, it reports if the plugin process exited on the call.
+func (s *suite) fetch(symbols []string) (types.PluginPriceReport, bool, error) {
+	report, err := s.pw.FetchPrices(symbols)
+	return report, err != nil && s.exited(), err
+}
+
+func checkState(s *suite) (Status, string) {
+	state, err := s.pw.State(s.conf.ChainID)
+	if err != nil {
+		return StatusFail, fmt.Sprintf("the state is rejected with the chain ID %d: %s", s.conf.ChainID, err.Error())
+	}
+	if state.Version == "" {
+		return StatusFail, "the version is not stated"
+	}
+	if len(state.AvailableSymbols) == 0 {
+		return StatusFail, "no symbols are available"
+	}
+
+	s.state = state
+	s.symbols = s.conf.Symbols
+	if len(s.symbols) == 0 {
+		for _, sym := range state.AvailableSymbols {
+			s.symbols = append(s.symbols, common2.ConvertSymbol(sym, "-"))
+		}
+	}
+	return StatusPass, ""
+}
+
+func checkChainIDMismatch(s *suite) (Status, string) {
+	mismatched := s.conf.ChainID + 1
+	if _, err := s.pw.State(mismatched); err == nil {
+		if s.exited() {
+			return StatusFail, "the plugin process exited"
+		}
+		return StatusSkip, fmt.Sprintf("the chain ID %d is accepted, the plugin is not bound to a chain", mismatched)
+	}
+	return StatusPass, ""
+}
+
+func checkSymbolForm(s *suite) (Status, string) {
+	if s.pw.ProtocolVersion() < types.ProtocolVersionSymbolMetadata {
+		return StatusSkip, fmt.Sprintf("the symbol metadata is not served by the protocol version %d", s.pw.ProtocolVersion())
+	}
+
+	var invalid []string
+	for _, meta := range s.state.Symbols {
+		if strings.Count(meta.Symbol, "-") != 1 || common2.ConvertSymbol(meta.Symbol, "-") != meta.Symbol {
+			invalid = append(invalid, meta.Symbol)
+		}
+	}
+	if len(invalid) != 0 {
+		return StatusFail, fmt.Sprintf("the stated symbols are not in - form: %v", invalid)
+	}
+	return StatusPass, ""
+}
+
+func checkEmptyInput(s *suite) (Status, string) {
+	report, exited, _ := s.fetch(nil)
+	if exited {
+		return StatusFail, "the plugin process exited on an empty input"
+	}
+	if len(report.Prices) != 0 {
+		return StatusFail, fmt.Sprintf("prices are returned for an empty input: %v", report.Prices)
+	}
+	return StatusPass, ""
+}
+
+func checkUnrecognizableSymbols(s *suite) (Status, string) {
+	report, exited, err := s.fetch([]string{unknownSymbol})
+	if exited {
+		return StatusFail, "the plugin process exited on an unknown symbol"
+	}
+	if len(report.Prices) != 0 {
+		return StatusFail, fmt.Sprintf("prices are returned for an unknown symbol: %v", report.Prices)
+	}
+	// the report is not delivered by the RPC with an error, thus the error itself tells the symbol is not recognized.
+	if err == nil && !contains(report.UnRecognizableSymbols, unknownSymbol) {
+		return StatusFail, fmt.Sprintf("the unknown symbol %s is not reported as unrecognizable", unknownSymbol)
+	}
+	return StatusPass, ""
+}
+
+func checkPrices(s *suite) (Status, string) {
+	asked := append(append([]string{}, s.symbols...), unknownSymbol)
+	report, exited, err := s.fetch(asked)
+	if exited {
+		return StatusFail, "the plugin process exited on fetching prices"
+	}
+	if err != nil {
+		return StatusFail, fmt.Sprintf("cannot fetch prices of %v: %s", s.symbols, err.Error())
+	}
+	if len(report.Prices) == 0 {
+		return StatusFail, fmt.Sprintf("no prices are returned for %v", s.symbols)
+	}
+	if !contains(report.UnRecognizableSymbols, unknownSymbol) {
+		return StatusFail, fmt.Sprintf("the unknown symbol %s is not reported as unrecognizable", unknownSymbol)
+	}
+
+	for _, p := range report.Prices {
+		switch {
+		case !contains(s.symbols, p.Symbol):
+			return StatusFail, fmt.Sprintf("the price of %s is not asked, or it is not in - form", p.Symbol)
+		case !p.Price.IsPositive():
+			return StatusFail, fmt.Sprintf("the price of %s is not positive: %s", p.Symbol, p.Price.String())
+		case p.Volume == nil || p.Volume.Sign() < 0:
+			return StatusFail, fmt.Sprintf("the volume of %s is not parseable: %v", p.Symbol, p.Volume)
+		case p.Timestamp <= 0:
+			return StatusFail, fmt.Sprintf("the timestamp of %s is missing", p.Symbol)
+		}
+	}
+	return StatusPass, ""
+}
+
+func checkDataSourceFailure(s *suite) (Status, string) {
+	s.standIn.SetFailure(true)
+	defer s.standIn.SetFailure(false)
+	if _, exited, _ := s.fetch(s.symbols); exited {
+		return StatusFail, "the plugin process exited on a failure of the data source"
+	}
+	return StatusPass, ""
+}
+
+func checkAlive(s *suite) (Status, string) {
+	if s.pw.Exited() {
+		return StatusFail, "the plugin process exited"
+	}
+	return StatusPass, ""
+}
+
+func contains(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
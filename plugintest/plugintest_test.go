@@ -0,0 +1,99 @@
+package plugintest
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStandIn(t *testing.T) {
+	standIn := NewStandIn(map[string]Fixture{
+		"api/price":  {Body: json.RawMessage(`{"price":"1.0"}`)},
+		"/api/limit": {Status: http.StatusTooManyRequests, Body: json.RawMessage(`{}`)},
+	})
+	defer standIn.Close()
+
+	get := func(path string) (int, string) {
+		resp, err := http.Get("http://" + standIn.Host() + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body)
+	}
+
+	t.Run("test fixtures are served by path", func(t *testing.T) {
+		status, body := get("/api/price?symbols=NTN-USD")
+		require.Equal(t, http.StatusOK, status)
+		require.Equal(t, `{"price":"1.0"}`, body)
+
+		status, _ = get("/api/limit")
+		require.Equal(t, http.StatusTooManyRequests, status)
+	})
+
+	t.Run("test unknown path", func(t *testing.T) {
+		status, _ := get("/api/unknown")
+		require.Equal(t, http.StatusNotFound, status)
+	})
+
+	t.Run("test failure", func(t *testing.T) {
+		standIn.SetFailure(true)
+		status, _ := get("/api/price")
+		require.Equal(t, http.StatusInternalServerError, status)
+		standIn.SetFailure(false)
+		status, _ = get("/api/price")
+		require.Equal(t, http.StatusOK, status)
+	})
+
+	require.Equal(t, 5, standIn.Requests())
+}
+
+func TestLoadFixtures(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"/api/price":{"status":200,"body":{"price":"1.0"}}}`), 0600))
+	fixtures, err := LoadFixtures(file)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, fixtures["/api/price"].Status)
+	require.JSONEq(t, `{"price":"1.0"}`, string(fixtures["/api/price"].Body))
+
+	require.NoError(t, os.WriteFile(file, []byte(`[]`), 0600))
+	_, err = LoadFixtures(file)
+	require.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	conf, err := ParseConfig([]string{"template_plugin", "-plugin-dir", "../plugins/template_plugin/bin"})
+	require.NoError(t, err)
+
+	results, err := Run(conf)
+	require.NoError(t, err)
+	require.False(t, Failed(results))
+	require.Equal(t, len(checks), len(results))
+	statuses := make(map[string]Status)
+	for _, r := range results {
+		statuses[r.Check] = r.Status
+	}
+	// the template plugin is not bound to a chain, and it takes the base protocol without the symbol metadata.
+	require.Equal(t, StatusSkip, statuses["chain id mismatch"])
+	require.Equal(t, StatusSkip, statuses["symbols in - form"])
+	require.Equal(t, StatusPass, statuses["prices"])
+
+	_, err = Run(&Config{PluginDIR: "../plugins/template_plugin/bin", PluginName: "unknown_plugin"})
+	require.Error(t, err)
+}
+
+func TestRunCLI(t *testing.T) {
+	var out bytes.Buffer
+	require.Equal(t, 1, RunCLI(nil, &out))
+	require.Contains(t, out.String(), errMissingPluginName.Error())
+
+	out.Reset()
+	require.Equal(t, 0, RunCLI([]string{"template_plugin", "-plugin-dir", "../plugins/template_plugin/bin"}, &out))
+	require.Contains(t, out.String(), "[pass] state")
+	require.Contains(t, out.String(), "plugin template_plugin conforms to the adapter contract")
+}
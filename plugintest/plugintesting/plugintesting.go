@@ -0,0 +1,24 @@
+// Package plugintesting runs the conformance test kit of the plugins from the go tests, it is apart from the plugintest
+// package, thus the testing package is not linked into the oracle server binary by the `plugin test` sub command.
+package plugintesting
+
+import (
+	"autonity-oracle/plugintest"
+	"testing"
+)
+
+// Require runs the battery of checks from a go test, the failed checks are reported as the errors of the test.
+func Require(t testing.TB, conf *plugintest.Config) {
+	t.Helper()
+	results, err := plugintest.Run(conf)
+	if err != nil {
+		t.Fatalf("cannot launch plugin %s: %s", conf.PluginName, err.Error())
+	}
+	for _, r := range results {
+		if r.Status == plugintest.StatusFail {
+			t.Errorf("plugin %s %s", conf.PluginName, r.String())
+		} else {
+			t.Log(r.String())
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package plugintesting
+
+import (
+	"autonity-oracle/plugintest"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestRequire(t *testing.T) {
+	conf, err := plugintest.ParseConfig([]string{"template_plugin", "-plugin-dir", "../../plugins/template_plugin/bin"})
+	require.NoError(t, err)
+	Require(t, conf)
+}
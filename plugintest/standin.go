@@ -0,0 +1,109 @@
+package plugintest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Fixture is a canned response of the stand-in.
+type Fixture struct {
+	Status int             `json:"status"` // the HTTP status code, default to 200.
+	Body   json.RawMessage `json:"body"`   // the JSON body of the response.
+}
+
+// StandIn is the local HTTP stand-in of a data source, it serves the fixtures by the URL path regardless of the query,
+// and it answers 404 for the unknown paths. It can be switched to fail all the requests with 500.
+type StandIn struct {
+	server   *http.Server
+	listener net.Listener
+	fixtures map[string]Fixture
+
+	lock     sync.RWMutex
+	failure  bool
+	requests int
+}
+
+// NewStandIn starts the stand-in with the fixtures by the URL path.
+func NewStandIn(fixtures map[string]Fixture) *StandIn {
+	s := &StandIn{fixtures: make(map[string]Fixture)}
+	for path, f := range fixtures {
+		s.fixtures["/"+strings.TrimPrefix(path, "/")] = f
+	}
+	// the server is not started by the httptest package, as it links the testing package into the oracle server binary.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("plugintest: failed to listen on a port: %v", err))
+	}
+	s.listener = listener
+	s.server = &http.Server{Handler: http.HandlerFunc(s.serve)} //nolint
+	go s.server.Serve(listener)                                 //nolint
+	return s
+}
+
+// LoadFixtures loads the fixtures from a JSON file of an object keyed by the URL path.
+func LoadFixtures(file string) (map[string]Fixture, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures map[string]Fixture
+	if err = json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("cannot decode fixtures: %w", err)
+	}
+	return fixtures, nil
+}
+
+func (s *StandIn) serve(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	s.requests++
+	failure := s.failure
+	s.lock.Unlock()
+
+	if failure {
+		http.Error(w, "stand-in failure", http.StatusInternalServerError)
+		return
+	}
+
+	f, ok := s.fixtures[r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	status := f.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(f.Body) //nolint
+}
+
+// Host returns the host:port of the stand-in, it is set as the endpoint of the plugin.
+func (s *StandIn) Host() string {
+	return s.listener.Addr().String()
+}
+
+// SetFailure switches the stand-in to fail all the requests with 500.
+func (s *StandIn) SetFailure(failure bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.failure = failure
+}
+
+// Requests returns the number of the requests served by the stand-in.
+func (s *StandIn) Requests() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.requests
+}
+
+func (s *StandIn) Close() {
+	s.server.Close() //nolint
+}
@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	received := make(chan *coltracepb.ExportTraceServiceRequest, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/traces", r.URL.Path)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		traces := &coltracepb.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, traces))
+		received <- traces
+	}))
+	defer srv.Close()
+
+	exporter, err := NewOTLPExporter(context.Background(), srv.URL+"/v1/traces")
+	require.NoError(t, err)
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer(ServiceName)
+
+	ctx, round := tracer.Start(context.Background(), "round")
+	round.SetAttributes(AttrRound.Int64(10))
+	_, agg := tracer.Start(ctx, "aggregate")
+	agg.SetAttributes(AttrSymbol.String("NTN-USD"))
+	EndWithError(agg, errors.New("no data"))
+
+	traces := <-received
+	require.Len(t, traces.ResourceSpans, 1)
+	require.Len(t, traces.ResourceSpans[0].ScopeSpans, 1)
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	require.Equal(t, "aggregate", spans[0].Name)
+	parentID := round.SpanContext().SpanID()
+	traceID := round.SpanContext().TraceID()
+	require.Equal(t, parentID[:], spans[0].ParentSpanId)
+	require.Equal(t, traceID[:], spans[0].TraceId)
+	require.Equal(t, "no data", spans[0].Status.Message)
+	require.Equal(t, string(AttrSymbol), spans[0].Attributes[0].Key)
+	require.Equal(t, "NTN-USD", spans[0].Attributes[0].Value.GetStringValue())
+
+	round.End()
+	traces = <-received
+	spans = traces.ResourceSpans[0].ScopeSpans[0].Spans
+	require.Equal(t, "round", spans[0].Name)
+	require.Empty(t, spans[0].ParentSpanId)
+	require.Equal(t, int64(10), spans[0].Attributes[0].Value.GetIntValue())
+	require.NoError(t, provider.Shutdown(context.Background()))
+}
+
+func TestOTLPExporterEndpoint(t *testing.T) {
+	_, err := NewOTLPExporter(context.Background(), "localhost:4318")
+	require.Error(t, err)
+	_, err = NewOTLPExporter(context.Background(), "ftp://localhost:4318/v1/traces")
+	require.Error(t, err)
+	_, err = NewOTLPExporter(context.Background(), "https://collector.local/v1/traces")
+	require.NoError(t, err)
+}
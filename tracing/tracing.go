@@ -0,0 +1,110 @@
+package tracing
+
+import (
+	"autonity-oracle/config"
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"os"
+)
+
+const (
+	ServiceName = "autonity-oracle"
+
+	// AttrRound and AttrSymbol share the key of the structured log fields, thus the spans and the log lines of a round
+	// can be correlated with the same key.
+	AttrRound  = attribute.Key(config.LogKeyRound)
+	AttrSymbol = attribute.Key(config.LogKeySymbol)
+	AttrPlugin = attribute.Key("plugin")
+)
+
+var enabled bool
+
+// Setup installs the process wide tracer provider with the exporters enabled in the trace configs, the spans are dropped
+// by the default no-op tracer provider if there is no exporter enabled. The returned shutdown flushes the pending spans.
+func Setup(conf *config.TraceConfig, version string) (func(context.Context) error, error) {
+	var options []sdktrace.TracerProviderOption
+	var closers []func() error
+
+	if conf.EnableOTLP {
+		exporter, err := NewOTLPExporter(context.Background(), conf.OTLPEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	switch conf.Exporter {
+	case config.TraceExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+	case config.TraceExporterFile:
+		f, err := os.OpenFile(conf.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		options = append(options, sdktrace.WithBatcher(exporter))
+		closers = append(closers, f.Close)
+	}
+
+	if len(options) == 0 {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res := resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(ServiceName),
+		semconv.ServiceVersion(version),
+	)
+	options = append(options, sdktrace.WithResource(res))
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+	enabled = true
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		for _, c := range closers {
+			if cErr := c(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// Enabled returns if there is any span exporter installed.
+func Enabled() bool {
+	return enabled
+}
+
+// Tracer returns the tracer of the oracle server.
+func Tracer() trace.Tracer {
+	return otel.Tracer(ServiceName)
+}
+
+// Start starts a span in the context, it is a shortcut of Tracer().Start().
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndWithError records the error if there is one, and ends the span.
+func EndWithError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
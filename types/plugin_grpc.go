@@ -0,0 +1,164 @@
+package types
+
+import (
+	"autonity-oracle/types/adapterpb"
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+	"google.golang.org/grpc"
+	"math/big"
+)
+
+// AdapterGRPCClient is an implementation of Adapter that talks over gRPC, it is used for the plugins which are served
+// with the gRPC protocol defined in types/adapterpb/adapter.proto, for example, the plugins written in other languages.
+type AdapterGRPCClient struct{ client adapterpb.AdapterClient }
+
+func (g *AdapterGRPCClient) FetchPrices(symbols []string) (PluginPriceReport, error) {
+	resp, err := g.client.FetchPrices(context.Background(), &adapterpb.FetchPricesRequest{Symbols: symbols})
+	if err != nil {
+		return PluginPriceReport{}, err
+	}
+
+	report := PluginPriceReport{UnRecognizableSymbols: resp.UnrecognizableSymbols}
+	for _, p := range resp.Prices {
+		price, err := decimal.NewFromString(p.Price)
+		if err != nil {
+			return PluginPriceReport{}, fmt.Errorf("invalid price %q of symbol %s: %w", p.Price, p.Symbol, err)
+		}
+
+		// the volume is optional in the protocol, a price without volume is weighted with the default volume.
+		volume := DefaultVolume
+		if p.Volume != "" {
+			v, ok := new(big.Int).SetString(p.Volume, 10)
+			if !ok {
+				return PluginPriceReport{}, fmt.Errorf("invalid volume %q of symbol %s", p.Volume, p.Symbol)
+			}
+			volume = v
+		}
+
+		report.Prices = append(report.Prices, Price{
+			Timestamp: p.Timestamp,
+			Symbol:    p.Symbol,
+			Price:     price,
+			Volume:    volume,
+		})
+	}
+	for _, m := range resp.Metrics {
+		report.Metrics = append(report.Metrics, Metric{Name: m.Name, Type: MetricType(m.Type), Value: m.Value})
+	}
+	return report, nil
+}
+
+func (g *AdapterGRPCClient) State(chainID int64) (PluginStatement, error) {
+	resp, err := g.client.State(context.Background(), &adapterpb.StateRequest{ChainId: chainID})
+	if err != nil {
+		return PluginStatement{}, err
+	}
+
+	state := PluginStatement{
+		KeyRequired:      resp.KeyRequired,
+		Version:          resp.Version,
+		DataSource:       resp.DataSource,
+		AvailableSymbols: resp.AvailableSymbols,
+		DataSourceType:   DataSourceType(resp.DataSourceType),
+	}
+	for _, m := range resp.Symbols {
+		state.Symbols = append(state.Symbols, SymbolMetadata{
+			Symbol:         m.Symbol,
+			QuoteCurrency:  m.QuoteCurrency,
+			Decimals:       int(m.Decimals),
+			UpdateInterval: m.UpdateInterval,
+			MarketHours:    MarketHours(m.MarketHours),
+			Derived:        m.Derived,
+		})
+	}
+	for _, f := range resp.ConfigSchema {
+		state.ConfigSchema = append(state.ConfigSchema, ConfigField{
+			Name:        f.Name,
+			Type:        ConfigFieldType(f.Type),
+			Required:    f.Required,
+			Default:     f.DefaultValue,
+			Secret:      f.Secret,
+			Description: f.Description,
+		})
+	}
+	return state, nil
+}
+
+// AdapterGRPCServer is the gRPC server that AdapterGRPCClient talks to, it serves a Go implementation of the Adapter.
+type AdapterGRPCServer struct {
+	adapterpb.UnimplementedAdapterServer
+	// This is the real implementation
+	Impl Adapter
+}
+
+func (s *AdapterGRPCServer) FetchPrices(_ context.Context, req *adapterpb.FetchPricesRequest) (*adapterpb.PriceReport, error) {
+	report, err := s.Impl.FetchPrices(req.Symbols)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &adapterpb.PriceReport{UnrecognizableSymbols: report.UnRecognizableSymbols}
+	for _, p := range report.Prices {
+		var volume string
+		if p.Volume != nil {
+			volume = p.Volume.String()
+		}
+		resp.Prices = append(resp.Prices, &adapterpb.Price{
+			Timestamp: p.Timestamp,
+			Symbol:    p.Symbol,
+			Price:     p.Price.String(),
+			Volume:    volume,
+		})
+	}
+	for _, m := range report.Metrics {
+		resp.Metrics = append(resp.Metrics, &adapterpb.Metric{Name: m.Name, Type: int32(m.Type), Value: m.Value}) //nolint
+	}
+	return resp, nil
+}
+
+func (s *AdapterGRPCServer) State(_ context.Context, req *adapterpb.StateRequest) (*adapterpb.PluginStatement, error) {
+	state, err := s.Impl.State(req.ChainId)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &adapterpb.PluginStatement{
+		KeyRequired:      state.KeyRequired,
+		Version:          state.Version,
+		DataSource:       state.DataSource,
+		AvailableSymbols: state.AvailableSymbols,
+		DataSourceType:   int32(state.DataSourceType), //nolint
+	}
+	for _, m := range state.Symbols {
+		resp.Symbols = append(resp.Symbols, &adapterpb.SymbolMetadata{
+			Symbol:         m.Symbol,
+			QuoteCurrency:  m.QuoteCurrency,
+			Decimals:       int32(m.Decimals), //nolint
+			UpdateInterval: m.UpdateInterval,
+			MarketHours:    int32(m.MarketHours), //nolint
+			Derived:        m.Derived,
+		})
+	}
+	for _, f := range state.ConfigSchema {
+		resp.ConfigSchema = append(resp.ConfigSchema, &adapterpb.ConfigField{
+			Name:         f.Name,
+			Type:         int32(f.Type), //nolint
+			Required:     f.Required,
+			DefaultValue: f.Default,
+			Secret:       f.Secret,
+			Description:  f.Description,
+		})
+	}
+	return resp, nil
+}
+
+func (p *AdapterPlugin) GRPCServer(_ *plugin.GRPCBroker, s *grpc.Server) error {
+	adapterpb.RegisterAdapterServer(s, &AdapterGRPCServer{Impl: p.Impl})
+	return nil
+}
+
+func (AdapterPlugin) GRPCClient(_ context.Context, _ *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &AdapterGRPCClient{client: adapterpb.NewAdapterClient(c)}, nil
+}
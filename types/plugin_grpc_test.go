@@ -0,0 +1,92 @@
+package types
+
+import (
+	"errors"
+	"github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"math/big"
+	"testing"
+	"time"
+)
+
+var errChainIDMismatch = errors.New("chain ID mismatch")
+
+var testStatement = PluginStatement{Version: "v0.0.1", DataSource: "test", AvailableSymbols: []string{"NTN-USD", "EUR-USD"},
+	DataSourceType: SrcCEX, KeyRequired: true, Symbols: []SymbolMetadata{
+		{Symbol: "NTN-USD", QuoteCurrency: "USD", Decimals: 18, UpdateInterval: 1},
+		{Symbol: "EUR-USD", QuoteCurrency: "USD", Decimals: 5, UpdateInterval: 60, MarketHours: MarketWeekdays},
+	}, ConfigSchema: []ConfigField{
+		{Name: "key", Type: ConfigString, Required: true, Secret: true},
+		{Name: "timeout", Type: ConfigInt, Default: "10", Description: "the timeout in seconds"},
+		{Name: "swapAddress", Type: ConfigAddress},
+	}}
+
+type testAdapter struct{}
+
+func (a *testAdapter) FetchPrices(symbols []string) (PluginPriceReport, error) {
+	var report PluginPriceReport
+	for _, s := range symbols {
+		if s != "NTN-USD" && s != "EUR-USD" {
+			report.UnRecognizableSymbols = append(report.UnRecognizableSymbols, s)
+			continue
+		}
+		p := Price{Timestamp: 100, Symbol: s, Price: decimal.RequireFromString("1.234")}
+		if s == "NTN-USD" {
+			p.Volume = big.NewInt(1000)
+		}
+		report.Prices = append(report.Prices, p)
+	}
+	report.Metrics = []Metric{{Name: "http/status/200", Type: MetricCounter, Value: 3}, {Name: "cache/hit_ratio", Type: MetricGauge, Value: 0.5}}
+	return report, nil
+}
+
+func (a *testAdapter) State(chainID int64) (PluginStatement, error) {
+	if chainID != 65100004 {
+		return PluginStatement{}, errChainIDMismatch
+	}
+	return testStatement, nil
+}
+
+func TestAdapterGRPC(t *testing.T) {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		"adapter": &AdapterPlugin{Impl: &testAdapter{}},
+	})
+	defer client.Close()
+	defer server.Stop()
+
+	raw, err := client.Dispense("adapter")
+	require.NoError(t, err)
+	adapter, ok := raw.(Adapter)
+	require.True(t, ok)
+
+	state, err := adapter.State(65100004)
+	require.NoError(t, err)
+	require.Equal(t, testStatement, state)
+
+	_, err = adapter.State(1)
+	require.ErrorContains(t, err, errChainIDMismatch.Error())
+
+	report, err := adapter.FetchPrices([]string{"NTN-USD", "EUR-USD", "ATN-USD"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"ATN-USD"}, report.UnRecognizableSymbols)
+	require.Equal(t, 2, len(report.Prices))
+	require.Equal(t, "NTN-USD", report.Prices[0].Symbol)
+	require.True(t, report.Prices[0].Price.Equal(decimal.RequireFromString("1.234")))
+	require.Equal(t, big.NewInt(1000), report.Prices[0].Volume)
+	require.Equal(t, int64(100), report.Prices[1].Timestamp)
+	require.Equal(t, DefaultVolume, report.Prices[1].Volume)
+	require.Equal(t, []Metric{{Name: "http/status/200", Type: MetricCounter, Value: 3}, {Name: "cache/hit_ratio", Type: MetricGauge, Value: 0.5}}, report.Metrics)
+}
+
+func TestMarketHours(t *testing.T) {
+	// 2024-01-05 is a Friday.
+	friday := time.Date(2024, 1, 5, 21, 59, 0, 0, time.UTC)
+	require.True(t, MarketWeekdays.IsOpen(friday))
+	require.False(t, MarketWeekdays.IsOpen(friday.Add(time.Minute)))
+	require.False(t, MarketWeekdays.IsOpen(friday.Add(24*time.Hour)))
+	require.False(t, MarketWeekdays.IsOpen(friday.Add(48*time.Hour)))
+	require.True(t, MarketWeekdays.IsOpen(friday.Add(48*time.Hour+time.Minute)))
+	require.True(t, MarketWeekdays.IsOpen(friday.Add(-24*time.Hour)))
+	require.True(t, MarketAlwaysOpen.IsOpen(friday.Add(24*time.Hour)))
+}
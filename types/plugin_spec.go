@@ -3,6 +3,7 @@ package types
 import (
 	"github.com/hashicorp/go-plugin"
 	"net/rpc"
+	"time"
 )
 
 // This file defines the autonity oracle plugins specification on top of go-plugin framework which leverage the localhost
@@ -17,6 +18,41 @@ const (
 	SrcAFQ
 )
 
+// MarketHours is the trading hours of the market of a symbol, the oracle server does not expect price updates while the
+// market is closed.
+type MarketHours int
+
+const (
+	MarketAlwaysOpen MarketHours = iota // crypto markets.
+	MarketWeekdays                      // forex markets, they close from Friday 22:00 to Sunday 22:00 in UTC.
+)
+
+// IsOpen returns true if the market is open at the time.
+func (m MarketHours) IsOpen(t time.Time) bool {
+	if m != MarketWeekdays {
+		return true
+	}
+
+	t = t.UTC()
+	switch t.Weekday() {
+	case time.Saturday:
+		return false
+	case time.Friday:
+		return t.Hour() < 22
+	case time.Sunday:
+		return t.Hour() >= 22
+	default:
+		return true
+	}
+}
+
+// The versions of the plugin protocol, they are negotiated on the launch of a plugin, thus the plugins built with an
+// older version of the protocol remain compatible with the oracle server.
+const (
+	ProtocolVersionBase           = 1 // the base protocol, the symbols are carried without metadata.
+	ProtocolVersionSymbolMetadata = 2 // the PluginStatement carries the metadata of per symbol.
+)
+
 // HandshakeConfig are used to just do a basic handshake between
 // a plugin and host. If the handshake fails, a user-friendly error is shown.
 // This prevents users from executing bad plugins or executing a plugin
@@ -27,11 +63,48 @@ var HandshakeConfig = plugin.HandshakeConfig{
 	MagicCookieValue: "hello",
 }
 
+// PluginConfFileEnv is the environment variable of a plugin process that carries the path of its configuration file,
+// the file is readable by the plugin only, and it is removed by the oracle server once the plugin is launched.
+const PluginConfFileEnv = "ORACLE_PLUGIN_CONF_FILE"
+
+// VersionedPlugins returns the plugin sets of the supported protocol versions, the highest common version of the oracle
+// server and the plugin is taken, while a legacy plugin which does not serve the versioned plugins takes the version
+// of the HandshakeConfig. The implementation is only required on the plugin side.
+func VersionedPlugins(impl Adapter) map[int]plugin.PluginSet {
+	return map[int]plugin.PluginSet{
+		ProtocolVersionBase:           {"adapter": &AdapterPlugin{Impl: impl}},
+		ProtocolVersionSymbolMetadata: {"adapter": &AdapterPlugin{Impl: impl}},
+	}
+}
+
+// AllowedProtocols are the protocols negotiated with a plugin on its launch, the plugin picks one of them in the
+// handshake. The Go plugins are served with net/rpc by default, while the plugins written in other languages can be
+// served with gRPC by the protocol defined in types/adapterpb/adapter.proto.
+var AllowedProtocols = []plugin.Protocol{plugin.ProtocolNetRPC, plugin.ProtocolGRPC}
+
 // PluginPriceReport is the returned data samples from adapters which carry the prices and those symbols of no data if
 // there are any unrecognisable symbols from the data source side.
 type PluginPriceReport struct {
 	Prices                []Price
 	UnRecognizableSymbols []string
+	// Metrics are the plugin-side metrics, they are registered under the plugin's namespace by the oracle server.
+	Metrics []Metric
+}
+
+// MetricType is the type of plugin-side metric.
+type MetricType int
+
+const (
+	MetricCounter MetricType = iota // the cumulative count since the plugin starts.
+	MetricGauge
+)
+
+// Metric is a plugin-side metric carried by the PluginPriceReport. A counter carries its cumulative count rather than
+// the increment, thus the counts are not lost with the reports that fail or time out.
+type Metric struct {
+	Name  string // the name under the plugin's namespace, for example, http/status/429.
+	Type  MetricType
+	Value float64
 }
 
 // PluginStatement is the returned when the oracle server loads a plugin.
@@ -41,6 +114,46 @@ type PluginStatement struct {
 	DataSource       string
 	AvailableSymbols []string
 	DataSourceType   DataSourceType
+	Streaming        bool // the plugin can push prices to the oracle server, it implements the PriceStreamer.
+	Historical       bool // the plugin can fetch the prices at a given time, it implements the HistoricalFetcher.
+	ReportsMetrics   bool // the plugin reports its metrics on demand, it implements the MetricsReporter.
+	// Symbols are the metadata of the available symbols, it is read since the ProtocolVersionSymbolMetadata.
+	Symbols []SymbolMetadata
+	// ConfigSchema describes the plugin config fields used by the plugin, the plugin config is validated against it
+	// on the launch of the plugin. It is empty if the plugin does not publish one, then the config is not validated.
+	ConfigSchema []ConfigField
+}
+
+// ConfigFieldType is the value type of a plugin config field.
+type ConfigFieldType int
+
+const (
+	ConfigString ConfigFieldType = iota
+	ConfigInt                    // a non-negative integer.
+	ConfigBool
+	ConfigAddress // a hex encoded address on the target blockchain.
+	ConfigMap     // a map of strings, for example, the symbolMap.
+	ConfigObject  // a nested object, for example, the budget.
+)
+
+// ConfigField describes a field of the plugin config that a plugin uses.
+type ConfigField struct {
+	Name        string // the yaml key of the field in the plugin config, for example, swapAddress.
+	Type        ConfigFieldType
+	Required    bool   // the field has to be set, either by the oracle server config or by its default.
+	Default     string // the default value applied by the plugin if the field is omitted, empty if there is no default.
+	Secret      bool   // the value is a credential, it is never printed.
+	Description string
+}
+
+// SymbolMetadata describes a symbol of a plugin, a zero value of a field means it is unknown.
+type SymbolMetadata struct {
+	Symbol         string
+	QuoteCurrency  string      // the quote currency of the symbol, for example, USD or USDC.
+	Decimals       int         // the native decimal precision of the price in the data source.
+	UpdateInterval int64       // the update interval of the price in the data source in seconds.
+	MarketHours    MarketHours // the trading hours of the market.
+	Derived        bool        // the price is derived from the prices of other symbols rather than being quoted directly.
 }
 
 // Adapter is the interface that we're exposing as a plugin.
@@ -57,8 +170,54 @@ type Adapter interface {
 	State(chainID int64) (PluginStatement, error)
 }
 
+// PriceSink receives the prices pushed by a streaming plugin. It returns ErrStreamBackpressure if it cannot take more
+// prices for now, the plugin should drop them and push the latest prices on the next update.
+type PriceSink interface {
+	PushPrices(prices []Price) error
+}
+
+// PriceStreamer is the optional capability of an Adapter to push the prices as they are updated in the data source,
+// rather than waiting to be polled by FetchPrices. It is declared by the Streaming flag of the PluginStatement.
+type PriceStreamer interface {
+	// StreamPrices starts to push the prices of the symbols into the sink, it returns once the stream is set up, and
+	// the previous stream of the plugin is replaced by the new one.
+	StreamPrices(symbols []string, sink PriceSink) error
+}
+
+// StreamArgs are the args to subscribe the price stream of a plugin, the plugin dials back the sink served on the
+// broker ID of the MuxBroker.
+type StreamArgs struct {
+	BrokerID uint32
+	Symbols  []string
+}
+
+// HistoricalFetcher is the optional capability of an Adapter to fetch the prices at a given time from the data source,
+// for example, from the candles of an exchange. It is declared by the Historical flag of the PluginStatement, and it is
+// called by the oracle server if the sampling missed the target timestamp of a round.
+type HistoricalFetcher interface {
+	// FetchPriceAt returns the prices of the symbols at the timestamp in seconds, the timestamp of a returned price is
+	// the time of the data point in the data source.
+	FetchPriceAt(symbols []string, ts int64) (PluginPriceReport, error)
+}
+
+// MetricsReporter is the optional capability of an Adapter to report its plugin-side metrics apart from the price
+// reports, thus the metrics of a plugin whose fetches fail, or of a streaming plugin which is not polled, are exported as
+// well. It is declared by the ReportsMetrics flag of the PluginStatement, and it is polled by the oracle server.
+type MetricsReporter interface {
+	ReportMetrics() ([]Metric, error)
+}
+
+// FetchAtArgs are the args to fetch the prices of the symbols at the timestamp.
+type FetchAtArgs struct {
+	Symbols []string
+	TS      int64
+}
+
 // AdapterRPCClient is an implementation that talks over RPC client
-type AdapterRPCClient struct{ client *rpc.Client }
+type AdapterRPCClient struct {
+	client *rpc.Client
+	broker *plugin.MuxBroker
+}
 
 func (g *AdapterRPCClient) FetchPrices(symbols []string) (PluginPriceReport, error) {
 	var resp PluginPriceReport
@@ -80,10 +239,38 @@ func (g *AdapterRPCClient) State(chainID int64) (PluginStatement, error) {
 	return resp, nil
 }
 
+func (g *AdapterRPCClient) FetchPriceAt(symbols []string, ts int64) (PluginPriceReport, error) {
+	var resp PluginPriceReport
+	err := g.client.Call("Plugin.FetchPriceAt", &FetchAtArgs{Symbols: symbols, TS: ts}, &resp)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (g *AdapterRPCClient) ReportMetrics() ([]Metric, error) {
+	var resp []Metric
+	err := g.client.Call("Plugin.ReportMetrics", 0, &resp)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+// StreamPrices serves the sink on a new connection of the MuxBroker, and asks the plugin to push prices into it.
+func (g *AdapterRPCClient) StreamPrices(symbols []string, sink PriceSink) error {
+	id := g.broker.NextId()
+	go g.broker.AcceptAndServe(id, &PriceSinkRPCServer{Impl: sink})
+	return g.client.Call("Plugin.StreamPrices", &StreamArgs{BrokerID: id, Symbols: symbols}, new(interface{}))
+}
+
 // AdapterRPCServer Here is the RPC server that AdapterRPCClient talks to, conforming to the requirements of net/rpc
 type AdapterRPCServer struct {
 	// This is the real implementation
-	Impl Adapter
+	Impl   Adapter
+	broker *plugin.MuxBroker
 }
 
 func (s *AdapterRPCServer) FetchPrices(symbols []string, resp *PluginPriceReport) error {
@@ -98,6 +285,68 @@ func (s *AdapterRPCServer) State(chainID int64, resp *PluginStatement) error {
 	return err
 }
 
+func (s *AdapterRPCServer) FetchPriceAt(args *FetchAtArgs, resp *PluginPriceReport) error {
+	fetcher, ok := s.Impl.(HistoricalFetcher)
+	if !ok {
+		return ErrHistoricalUnsupported
+	}
+
+	report, err := fetcher.FetchPriceAt(args.Symbols, args.TS)
+	*resp = report
+	return err
+}
+
+func (s *AdapterRPCServer) ReportMetrics(_ int, resp *[]Metric) error {
+	reporter, ok := s.Impl.(MetricsReporter)
+	if !ok {
+		return ErrMetricsUnsupported
+	}
+
+	ms, err := reporter.ReportMetrics()
+	*resp = ms
+	return err
+}
+
+func (s *AdapterRPCServer) StreamPrices(args *StreamArgs, _ *interface{}) error {
+	streamer, ok := s.Impl.(PriceStreamer)
+	if !ok {
+		return ErrStreamingUnsupported
+	}
+
+	conn, err := s.broker.Dial(args.BrokerID)
+	if err != nil {
+		return err
+	}
+
+	return streamer.StreamPrices(args.Symbols, &PriceSinkRPCClient{client: rpc.NewClient(conn)})
+}
+
+// PriceSinkRPCClient is the sink in the plugin side that pushes the prices back to the oracle server.
+type PriceSinkRPCClient struct{ client *rpc.Client }
+
+func (c *PriceSinkRPCClient) PushPrices(prices []Price) error {
+	err := c.client.Call("Plugin.PushPrices", prices, new(interface{}))
+	// the error is transported in string by net/rpc, recover it for the plugin to handle the backpressure.
+	if err != nil && err.Error() == ErrStreamBackpressure.Error() {
+		return ErrStreamBackpressure
+	}
+	return err
+}
+
+// Close closes the connection of the sink, it is called by the plugin once the stream is replaced or stopped.
+func (c *PriceSinkRPCClient) Close() error {
+	return c.client.Close()
+}
+
+// PriceSinkRPCServer is the RPC server that PriceSinkRPCClient talks to in the oracle server side.
+type PriceSinkRPCServer struct {
+	Impl PriceSink
+}
+
+func (s *PriceSinkRPCServer) PushPrices(prices []Price, _ *interface{}) error {
+	return s.Impl.PushPrices(prices)
+}
+
 // AdapterPlugin is the unified implementation of plugins, all the 3rd parties plugins need to inject their
 // implementation by using this structure in their source code.
 type AdapterPlugin struct {
@@ -105,10 +354,10 @@ type AdapterPlugin struct {
 	Impl Adapter
 }
 
-func (p *AdapterPlugin) Server(*plugin.MuxBroker) (interface{}, error) {
-	return &AdapterRPCServer{Impl: p.Impl}, nil
+func (p *AdapterPlugin) Server(b *plugin.MuxBroker) (interface{}, error) {
+	return &AdapterRPCServer{Impl: p.Impl, broker: b}, nil
 }
 
-func (AdapterPlugin) Client(_ *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
-	return &AdapterRPCClient{client: c}, nil
+func (AdapterPlugin) Client(b *plugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &AdapterRPCClient{client: c, broker: b}, nil
 }
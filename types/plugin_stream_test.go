@@ -0,0 +1,113 @@
+package types
+
+import (
+	"github.com/hashicorp/go-plugin"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+type testStreamer struct {
+	testAdapter
+	pushed chan error
+}
+
+func (s *testStreamer) StreamPrices(symbols []string, sink PriceSink) error {
+	report, err := s.FetchPrices(symbols)
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := sink.PushPrices(report.Prices); err != nil {
+			s.pushed <- err
+			return
+		}
+		s.pushed <- sink.PushPrices(report.Prices)
+	}()
+	return nil
+}
+
+type testSink struct {
+	ch chan []Price
+}
+
+func (s *testSink) PushPrices(prices []Price) error {
+	select {
+	case s.ch <- prices:
+		return nil
+	default:
+		return ErrStreamBackpressure
+	}
+}
+
+func TestAdapterStreamPrices(t *testing.T) {
+	streamer := &testStreamer{pushed: make(chan error, 1)}
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"adapter": &AdapterPlugin{Impl: streamer},
+	}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense("adapter")
+	require.NoError(t, err)
+	adapter, ok := raw.(PriceStreamer)
+	require.True(t, ok)
+
+	sink := &testSink{ch: make(chan []Price, 1)}
+	require.NoError(t, adapter.StreamPrices([]string{"NTN-USD"}, sink))
+
+	// the second push is rejected by the congested sink, and the error is recovered in the plugin side.
+	select {
+	case err = <-streamer.pushed:
+		require.Equal(t, ErrStreamBackpressure, err)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "no prices are pushed")
+	}
+
+	prices := <-sink.ch
+	require.Equal(t, 1, len(prices))
+	require.Equal(t, "NTN-USD", prices[0].Symbol)
+	require.True(t, prices[0].Price.Equal(decimal.RequireFromString("1.234")))
+}
+
+func TestAdapterStreamUnsupported(t *testing.T) {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"adapter": &AdapterPlugin{Impl: &testAdapter{}},
+	}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense("adapter")
+	require.NoError(t, err)
+	err = raw.(PriceStreamer).StreamPrices([]string{"NTN-USD"}, &testSink{ch: make(chan []Price, 1)})
+	require.ErrorContains(t, err, ErrStreamingUnsupported.Error())
+}
+
+type testReporter struct {
+	testAdapter
+}
+
+func (r *testReporter) ReportMetrics() ([]Metric, error) {
+	return []Metric{{Name: "swap_events", Type: MetricCounter, Value: 3}}, nil
+}
+
+func TestAdapterReportMetrics(t *testing.T) {
+	client, _ := plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"adapter": &AdapterPlugin{Impl: &testReporter{}},
+	}, nil)
+	defer client.Close()
+
+	raw, err := client.Dispense("adapter")
+	require.NoError(t, err)
+	ms, err := raw.(MetricsReporter).ReportMetrics()
+	require.NoError(t, err)
+	require.Equal(t, []Metric{{Name: "swap_events", Type: MetricCounter, Value: 3}}, ms)
+
+	client, _ = plugin.TestPluginRPCConn(t, map[string]plugin.Plugin{
+		"adapter": &AdapterPlugin{Impl: &testAdapter{}},
+	}, nil)
+	defer client.Close()
+	raw, err = client.Dispense("adapter")
+	require.NoError(t, err)
+	_, err = raw.(MetricsReporter).ReportMetrics()
+	require.ErrorContains(t, err, ErrMetricsUnsupported.Error())
+}
@@ -11,19 +11,30 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/shopspring/decimal"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
 	Deployer                = common.Address{}
 	DefaultVolume           = new(big.Int).SetInt64(1000000) // used by forex currency which does not have trade volumes.
 	AutonityContractAddress = crypto.CreateAddress(Deployer, 0)
-	OracleContractAddress   = crypto.CreateAddress(Deployer, 2)
+	OracleContractAddress   = crypto.CreateAddress(Deployer, 2) // the default one, it can be overridden by the config.
 
-	ErrPeerOnSync        = errors.New("l1 node is on peer sync")
-	ErrNoAvailablePrice  = errors.New("no available prices collected yet")
-	ErrNoDataRound       = errors.New("no data collected at current round")
-	ErrNoSymbolsObserved = errors.New("no symbols observed from oracle contract")
-	ErrMissingServiceKey = errors.New("the key to access the data source is missing, please check the plugin config")
+	ErrPeerOnSync            = errors.New("l1 node is on peer sync")
+	ErrNoAvailablePrice      = errors.New("no available prices collected yet")
+	ErrNoDataRound           = errors.New("no data collected at current round")
+	ErrNoSymbolsObserved     = errors.New("no symbols observed from oracle contract")
+	ErrMissingServiceKey     = errors.New("the key to access the data source is missing, please check the plugin config")
+	ErrInvalidPluginConfig   = errors.New("the plugin config does not match the config schema of the plugin")
+	ErrTxInclusionTimeout    = errors.New("tx is not included in time")
+	ErrStalePrice            = errors.New("the price is stale")
+	ErrStreamingUnsupported  = errors.New("price streaming is not supported by the plugin")
+	ErrStreamBackpressure    = errors.New("price stream is congested, the prices are dropped")
+	ErrPluginIntegrity       = errors.New("the plugin binary is not trusted")
+	ErrFetchTimeout          = errors.New("the plugin did not return the prices in time")
+	ErrPluginBusy            = errors.New("the plugin is still busy with a timed out fetch")
+	ErrHistoricalUnsupported = errors.New("historical prices are not supported by the plugin")
+	ErrMetricsUnsupported    = errors.New("metrics reporting is not supported by the plugin")
 )
 
 // Price is the structure contains the exchange rate of a symbol with a timestamp at which the sampling happens.
@@ -49,6 +60,15 @@ type RoundData struct {
 	Symbols        []string
 	Reports        []contract.IOracleReport
 	MissingData    bool
+	Audit          RoundAudit
+	PluginPrices   map[string]map[string]decimal.Decimal // the prices of per plugin by symbol, to track their deviation.
+}
+
+// RoundAudit records the commit-reveal consistency of a round's data.
+type RoundAudit struct {
+	CommitIncluded bool   // the commitment tx of the round was included on chain successfully.
+	RevealSkipped  bool   // the reveal of the round was skipped as there is no matching commitment on chain.
+	Reason         string // the reason of the skipped reveal.
 }
 
 // JSONRPCMessage is the JSON spec to carry those data response from the binance data simulator.
@@ -62,6 +82,7 @@ type JSONRPCMessage struct {
 
 // SampleEvent carry the symbols and TS for the data sampling.
 type SampleEvent struct {
-	Symbols []string
-	TS      int64
+	Symbols  []string
+	TS       int64
+	TraceCtx trace.SpanContext // the span context of the sampling, the data fetching of plugins are traced as its children.
 }